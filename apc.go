@@ -21,7 +21,7 @@ func (t *Terminal) handleAPC(code string) {
 		}
 	}
 
-	if t.debug {
+	if t.screen.debug {
 		// Handle other APC sequences or log the received APC code
 		log.Println("Unrecognised APC", code)
 	}
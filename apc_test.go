@@ -22,13 +22,17 @@ func TestAPC(t *testing.T) {
 			input:    append([]byte("\x1b_set apcstring:Hello"), 0),
 			expected: "Hello",
 		},
+		"terminated by ST": {
+			input:    []byte("\x1b_set apcstring:World\x1b\\"),
+			expected: "World",
+		},
 	}
 
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
 			term := New()
 			term.Resize(fyne.NewSize(50, 50))
-			term.handleOutput(testCase.input)
+			term.screen.handleOutput(testCase.input)
 
 			assert.Equal(t, testCase.expected, APCString)
 		})
@@ -0,0 +1,72 @@
+package terminal
+
+import "fyne.io/fyne/v2"
+
+// ClipboardPolicy decides whether applications can read or write the clipboard using OSC 52.
+// Reading is a concern as any program, including those on remote hosts, could see what the user has copied.
+type ClipboardPolicy int
+
+const (
+	// ClipboardAllowWrite lets applications set the clipboard but not read it. This is the default.
+	ClipboardAllowWrite ClipboardPolicy = iota
+	// ClipboardAllowRead lets applications both set and read the clipboard.
+	ClipboardAllowRead
+	// ClipboardAsk calls Terminal.OnClipboardRequest to decide each access, denying it if that is nil.
+	ClipboardAsk
+	// ClipboardDeny ignores all clipboard requests from applications.
+	ClipboardDeny
+)
+
+// allowClipboard calls allowed if the policy permits the read or write, possibly after asking OnClipboardRequest.
+func (t *Terminal) allowClipboard(read bool, allowed func()) {
+	switch t.ClipboardPolicy {
+	case ClipboardAllowWrite:
+		if !read {
+			allowed()
+		}
+	case ClipboardAllowRead:
+		allowed()
+	case ClipboardAsk:
+		if t.OnClipboardRequest == nil {
+			return
+		}
+		t.OnClipboardRequest(read, func(allow bool) {
+			if allow {
+				allowed()
+			}
+		})
+	}
+}
+
+// clipboardFor returns the clipboard for an OSC 52 selection, or nil if it is not supported.
+func (t *Terminal) clipboardFor(selection rune) fyne.Clipboard {
+	switch selection {
+	case 'c':
+		return fyne.CurrentApp().Clipboard()
+	case 'p', 's':
+		return t.selectClipboard()
+	}
+	return nil
+}
+
+func (t *Terminal) readClipboard(selection rune, reply func(string)) {
+	clip := t.clipboardFor(selection)
+	if clip == nil {
+		return
+	}
+
+	t.allowClipboard(true, func() {
+		reply(clip.Content())
+	})
+}
+
+func (t *Terminal) writeClipboard(selection rune, content string) {
+	clip := t.clipboardFor(selection)
+	if clip == nil {
+		return
+	}
+
+	t.allowClipboard(false, func() {
+		clip.SetContent(content)
+	})
+}
@@ -0,0 +1,55 @@
+package terminal
+
+import (
+	"bytes"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerminal_OSCClipboard(t *testing.T) {
+	test.NewApp()
+	term := New()
+	reply := &bytes.Buffer{}
+	term.in = NopCloser(reply)
+	clip := fyne.CurrentApp().Clipboard()
+	clip.SetContent("before")
+
+	_, _ = term.screen.Write([]byte("\x1b]52;c;aGVsbG8=\x07\x1b]52;p;c2VsZWN0\x1b\\"))
+	assert.Equal(t, "hello", clip.Content())
+	assert.Equal(t, "select", term.selectClipboard().Content())
+
+	_, _ = term.screen.Write([]byte("\x1b]52;c;?\x07"))
+	assert.Equal(t, "", reply.String())
+
+	term.ClipboardPolicy = ClipboardAllowRead
+	_, _ = term.screen.Write([]byte("\x1b]52;;?\x07"))
+	assert.Equal(t, "\x1b]52;c;aGVsbG8=\x07", reply.String())
+
+	term.ClipboardPolicy = ClipboardDeny
+	_, _ = term.screen.Write([]byte("\x1b]52;c;d29ybGQ=\x07"))
+	assert.Equal(t, "hello", clip.Content())
+
+	var asked []bool
+	var respond func(bool)
+	term.ClipboardPolicy = ClipboardAsk
+	term.OnClipboardRequest = func(read bool, r func(bool)) {
+		asked = append(asked, read)
+		respond = r
+	}
+	reply.Reset()
+	_, _ = term.screen.Write([]byte("\x1b]52;c;d29ybGQ=\x07"))
+	assert.Equal(t, "hello", clip.Content())
+	respond(true)
+	assert.Equal(t, "world", clip.Content())
+
+	_, _ = term.screen.Write([]byte("\x1b]52;c;?\x1b\\"))
+	respond(false)
+	assert.Equal(t, "", reply.String())
+	_, _ = term.screen.Write([]byte("\x1b]52;c;?\x1b\\"))
+	respond(true)
+	assert.Equal(t, "\x1b]52;c;d29ybGQ=\x1b\\", reply.String())
+	assert.Equal(t, []bool{false, true, true}, asked)
+}
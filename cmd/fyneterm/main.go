@@ -54,10 +54,18 @@ func main() {
 
 func findTerminal(item *container.TabItem) *terminal.Terminal {
 	override := item.Content.(*container.ThemeOverride)
-	stack := override.Content.(*fyne.Container)
-	for _, obj := range stack.Objects {
-		if t, ok := obj.(*terminal.Terminal); ok {
-			return t
+	return findTerminalIn(override.Content)
+}
+
+func findTerminalIn(o fyne.CanvasObject) *terminal.Terminal {
+	switch obj := o.(type) {
+	case *terminal.Terminal:
+		return obj
+	case *fyne.Container:
+		for _, child := range obj.Objects {
+			if t := findTerminalIn(child); t != nil {
+				return t
+			}
 		}
 	}
 	return nil
@@ -172,12 +180,24 @@ func newTab(tabs *container.DocTabs, refresh func(), debug bool, th *termTheme,
 		}
 	}
 
+	t.OnNotification = func(title, body string) {
+		if t.Focused() {
+			return
+		}
+		if title == "" {
+			title = termTitle()
+		}
+		a.SendNotification(fyne.NewNotification(title, body))
+	}
+
 	a.Settings().AddListener(func(s fyne.Settings) {
 		bg.FillColor = theme.Color(theme.ColorNameBackground)
 		bg.Refresh()
 	})
 
-	sizeOverride := container.NewThemeOverride(container.NewStack(bg, img, t), th)
+	search := newSearchBar(t, w.Canvas())
+	content := container.NewBorder(nil, search, nil, nil, container.NewStack(bg, img, t))
+	sizeOverride := container.NewThemeOverride(content, th)
 	tabItem := container.NewTabItem(termTitle(), sizeOverride)
 
 	listen := make(chan terminal.Config)
@@ -231,6 +251,25 @@ func newTab(tabs *container.DocTabs, refresh func(), debug bool, th *termTheme,
 		t.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyT, Modifier: fyne.KeyModifierSuper}, newTabShortcut)
 	}
 
+	// Search shortcut
+	showSearch := func(_ fyne.Shortcut) {
+		search.show()
+	}
+	t.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierControl | fyne.KeyModifierShift}, showSearch)
+	if runtime.GOOS == "darwin" {
+		t.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierSuper}, showSearch)
+	}
+
+	// Prompt navigation shortcuts, these need the shell integration of OSC 133
+	t.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyUp, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift},
+		func(_ fyne.Shortcut) {
+			t.ScrollToPreviousPrompt()
+		})
+	t.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyDown, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift},
+		func(_ fyne.Shortcut) {
+			t.ScrollToNextPrompt()
+		})
+
 	// Font size shortcuts
 	refreshAllTabs := func() {
 		for _, item := range tabs.Items {
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/fyne-io/terminal"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// searchBar lets the user find text in a terminal and its history.
+type searchBar struct {
+	*fyne.Container
+
+	term   *terminal.Terminal
+	canvas fyne.Canvas
+	entry  *searchEntry
+	status *widget.Label
+
+	opts             terminal.SearchOptions
+	current, matches int
+}
+
+func newSearchBar(t *terminal.Terminal, c fyne.Canvas) *searchBar {
+	s := &searchBar{term: t, canvas: c, status: widget.NewLabel("")}
+	s.entry = newSearchEntry(s.hide)
+	s.entry.SetPlaceHolder(lang.L("Find"))
+	s.entry.OnChanged = func(string) {
+		s.find()
+	}
+	s.entry.OnSubmitted = func(string) {
+		s.previous()
+	}
+
+	matchCase := widget.NewCheck(lang.L("Match case"), func(on bool) {
+		s.opts.CaseSensitive = on
+		s.find()
+	})
+	regexp := widget.NewCheck(lang.L("Regular expression"), func(on bool) {
+		s.opts.Regexp = on
+		s.find()
+	})
+	buttons := container.NewHBox(s.status, matchCase, regexp,
+		widget.NewButtonWithIcon("", theme.MoveUpIcon(), s.previous),
+		widget.NewButtonWithIcon("", theme.MoveDownIcon(), s.next),
+		widget.NewButtonWithIcon("", theme.CancelIcon(), s.hide))
+
+	s.Container = container.NewBorder(nil, nil, nil, buttons, s.entry)
+	s.Container.Hide()
+	return s
+}
+
+func (s *searchBar) find() {
+	found, err := s.term.Find(s.entry.Text, s.opts)
+	s.matches = len(found)
+	s.current = len(found) - 1
+	switch {
+	case err != nil:
+		s.status.SetText(lang.L("Invalid expression"))
+	case s.entry.Text == "":
+		s.status.SetText("")
+	case len(found) == 0:
+		s.status.SetText(lang.L("No results"))
+	default:
+		s.updateStatus()
+	}
+}
+
+func (s *searchBar) hide() {
+	s.term.ClearSearch()
+	s.Container.Hide()
+	s.canvas.Focus(s.term)
+}
+
+func (s *searchBar) next() {
+	if _, ok := s.term.FindNext(); ok {
+		s.current = (s.current + 1) % s.matches
+		s.updateStatus()
+	}
+}
+
+func (s *searchBar) previous() {
+	if _, ok := s.term.FindPrevious(); ok {
+		s.current = (s.current - 1 + s.matches) % s.matches
+		s.updateStatus()
+	}
+}
+
+func (s *searchBar) show() {
+	s.Container.Show()
+	s.canvas.Focus(s.entry)
+	s.find()
+}
+
+func (s *searchBar) updateStatus() {
+	s.status.SetText(strconv.Itoa(s.current+1) + "/" + strconv.Itoa(s.matches))
+}
+
+// searchEntry is an Entry that closes the search when Escape is pressed.
+type searchEntry struct {
+	widget.Entry
+
+	onEscape func()
+}
+
+func newSearchEntry(onEscape func()) *searchEntry {
+	e := &searchEntry{onEscape: onEscape}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *searchEntry) TypedKey(ev *fyne.KeyEvent) {
+	if ev.Name == fyne.KeyEscape {
+		e.onEscape()
+		return
+	}
+
+	e.Entry.TypedKey(ev)
+}
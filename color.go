@@ -7,50 +7,18 @@ import (
 	"strings"
 
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/theme"
+	widget2 "github.com/fyne-io/terminal/internal/widget"
 )
 
-var (
-	basicColors = []color.Color{
-		color.Black,
-		&color.RGBA{170, 0, 0, 255},
-		&color.RGBA{0, 170, 0, 255},
-		&color.RGBA{170, 170, 0, 255},
-		&color.RGBA{0, 0, 170, 255},
-		&color.RGBA{170, 0, 170, 255},
-		&color.RGBA{0, 255, 255, 255},
-		&color.RGBA{170, 170, 170, 255},
-	}
-	brightColors = []color.Color{
-		&color.RGBA{85, 85, 85, 255},
-		&color.RGBA{255, 85, 85, 255},
-		&color.RGBA{85, 255, 85, 255},
-		&color.RGBA{255, 255, 85, 255},
-		&color.RGBA{85, 85, 255, 255},
-		&color.RGBA{255, 85, 255, 255},
-		&color.RGBA{85, 255, 255, 255},
-		&color.RGBA{255, 255, 255, 255},
-	}
-	colourBands = []uint8{
-		0x00,
-		0x5f,
-		0x87,
-		0xaf,
-		0xd7,
-		0xff,
-	}
-)
-
-func (t *Terminal) handleColorEscape(message string) {
+func (s *Screen) handleColorEscape(message string) {
 	if message == "" || message == "0" {
-		t.currentBG = nil
-		t.currentFG = nil
-		t.bold = false
-		t.blinking = false
+		s.currentBG = nil
+		s.currentFG = nil
+		s.resetAttributes()
 		return
 	}
 	if message[0] == '>' || message[0] == '?' {
-		if t.debug {
+		if s.debug {
 			log.Println("Strange colour mode", message)
 		}
 		return
@@ -62,22 +30,50 @@ func (t *Terminal) handleColorEscape(message string) {
 			continue
 		}
 
-		if (mode == "38" || mode == "48") && i+1 < len(modes) {
+		if strings.Contains(mode, ":") {
+			s.handleColorSubParams(strings.Split(mode, ":"))
+		} else if (mode == "38" || mode == "48" || mode == "58") && i+1 < len(modes) {
 			nextMode := modes[i+1]
 			if nextMode == "5" && i+2 < len(modes) {
-				t.handleColorModeMap(mode, modes[i+2])
+				s.handleColorModeMap(mode, modes[i+2])
 				i += 2
 			} else if nextMode == "2" && i+4 < len(modes) {
-				t.handleColorModeRGB(mode, modes[i+2], modes[i+3], modes[i+4])
+				s.handleColorModeRGB(mode, modes[i+2], modes[i+3], modes[i+4])
 				i += 4
 			}
 		} else {
-			t.handleColorMode(mode)
+			s.handleColorMode(mode)
 		}
 	}
 }
 
-func (t *Terminal) handleColorMode(modeStr string) {
+// handleColorSubParams handles a mode with colon separated parameters, such as "4:3" for a curly underline
+// or "38:2::255:0:0" for an RGB colour with an (ignored) colour space ID.
+func (s *Screen) handleColorSubParams(params []string) {
+	switch params[0] {
+	case "4":
+		style, _ := strconv.Atoi(params[1])
+		if style > int(widget2.UnderlineDashed) {
+			style = int(widget2.UnderlineSingle)
+		}
+		s.underline = widget2.UnderlineStyle(style)
+	case "38", "48", "58":
+		switch {
+		case params[1] == "5" && len(params) > 2:
+			s.handleColorModeMap(params[0], params[2])
+		case params[1] == "2" && len(params) > 5:
+			s.handleColorModeRGB(params[0], params[3], params[4], params[5])
+		case params[1] == "2" && len(params) > 4:
+			s.handleColorModeRGB(params[0], params[2], params[3], params[4])
+		}
+	default:
+		if s.debug {
+			log.Println("Unsupported graphics mode", strings.Join(params, ":"))
+		}
+	}
+}
+
+func (s *Screen) handleColorMode(modeStr string) {
 	mode, err := strconv.Atoi(modeStr)
 	if err != nil {
 		fyne.LogError("Failed to parse color mode: "+modeStr, err)
@@ -85,102 +81,112 @@ func (t *Terminal) handleColorMode(modeStr string) {
 	}
 	switch mode {
 	case 0:
-		t.currentBG, t.currentFG = nil, nil
-		t.bold = false
-		t.blinking = false
+		s.currentBG, s.currentFG = nil, nil
+		s.resetAttributes()
 	case 1:
-		t.bold = true
-	case 4, 24: //italic
-	case 5:
-		t.blinking = true
-	case 7: // reverse
-		bg, fg := t.currentBG, t.currentFG
-		if fg == nil {
-			t.currentBG = theme.Color(theme.ColorNameForeground)
-		} else {
-			t.currentBG = fg
-		}
-		if bg == nil {
-			t.currentFG = theme.Color(theme.ColorNameDisabledButton)
-		} else {
-			t.currentFG = bg
-		}
-	case 27: // reverse off
-		bg, fg := t.currentBG, t.currentFG
-		if fg != nil {
-			t.currentBG = nil
-		} else {
-			t.currentBG = fg
-		}
-		if bg != nil {
-			t.currentFG = nil
-		} else {
-			t.currentFG = bg
-		}
+		s.bold = true
+	case 2:
+		s.dim = true
+	case 3:
+		s.italic = true
+	case 4:
+		s.underline = widget2.UnderlineSingle
+	case 5, 6:
+		s.blinking = true
+	case 7:
+		s.inverse = true
+	case 8:
+		s.invisible = true
+	case 9:
+		s.strikethrough = true
+	case 21:
+		s.underline = widget2.UnderlineDouble
+	case 22:
+		s.bold, s.dim = false, false
+	case 23:
+		s.italic = false
+	case 24:
+		s.underline = widget2.UnderlineNone
+	case 25:
+		s.blinking = false
+	case 27:
+		s.inverse = false
+	case 28:
+		s.invisible = false
+	case 29:
+		s.strikethrough = false
 	case 30, 31, 32, 33, 34, 35, 36, 37:
-		t.currentFG = basicColors[mode-30]
+		s.currentFG = s.paletteColor(mode - 30)
 	case 39:
-		t.currentFG = nil
+		s.currentFG = nil
 	case 40, 41, 42, 43, 44, 45, 46, 47:
-		t.currentBG = basicColors[mode-40]
+		s.currentBG = s.paletteColor(mode - 40)
 	case 49:
-		t.currentBG = nil
+		s.currentBG = nil
+	case 59:
+		s.underlineColor = nil
 	case 90, 91, 92, 93, 94, 95, 96, 97:
-		t.currentFG = brightColors[mode-90]
+		s.currentFG = s.paletteColor(mode - 90 + 8)
 	case 100, 101, 102, 103, 104, 105, 106, 107:
-		t.currentBG = brightColors[mode-100]
+		s.currentBG = s.paletteColor(mode - 100 + 8)
 	default:
-		if t.debug {
+		if s.debug {
 			log.Println("Unsupported graphics mode", mode)
 		}
 	}
 }
 
-func (t *Terminal) handleColorModeMap(mode, ids string) {
+// brightColor returns the bright version of one of the basic colours, so bold text stands out
+// even when the monospace font has no bold face. Other colours are returned unchanged.
+func brightColor(c color.Color) color.Color {
+	if p, ok := c.(widget2.PaletteColor); ok && p.Index < 8 {
+		p.Index += 8
+		return p
+	}
+	return c
+}
+
+func (s *Screen) resetAttributes() {
+	s.bold, s.dim, s.italic, s.strikethrough = false, false, false, false
+	s.underline, s.underlineColor = widget2.UnderlineNone, nil
+	s.blinking, s.inverse, s.invisible = false, false, false
+}
+
+func (s *Screen) handleColorModeMap(mode, ids string) {
 	var c color.Color
 	id, err := strconv.Atoi(ids)
 	if err != nil {
-		if t.debug {
+		if s.debug {
 			log.Println("Invalid color map ID", ids)
 		}
 		return
 	}
-	if id <= 7 {
-		c = basicColors[id]
-	} else if id <= 15 {
-		c = brightColors[id-8]
-	} else if id <= 231 {
-		id -= 16
-		b := id % 6
-		id = (id - b) / 6
-		g := id % 6
-		r := (id - g) / 6
-		c = &color.RGBA{colourBands[r], colourBands[g], colourBands[b], 255}
-	} else if id <= 255 {
-		id -= 232
-		inc := 256 / 24
-		y := id * inc
-		c = &color.Gray{uint8(y)}
-	} else if t.debug {
+	if id >= 0 && id <= 255 {
+		c = s.paletteColor(id)
+	} else if s.debug {
 		log.Println("Invalid colour map ID", id)
 	}
 
-	if mode == "38" {
-		t.currentFG = c
-	} else if mode == "48" {
-		t.currentBG = c
-	}
+	s.setColor(mode, c)
 }
 
-func (t *Terminal) handleColorModeRGB(mode, rs, gs, bs string) {
+func (s *Screen) handleColorModeRGB(mode, rs, gs, bs string) {
 	r, _ := strconv.Atoi(rs)
 	g, _ := strconv.Atoi(gs)
 	b, _ := strconv.Atoi(bs)
 	c := &color.RGBA{uint8(r), uint8(g), uint8(b), 255}
 
-	if mode == "38" {
-		t.currentFG = c
-	} else if mode == "48" {
-		t.currentBG = c
+	s.setColor(mode, c)
+}
+
+// setColor sets the text, background or underline colour for a 38, 48 or 58 mode.
+func (s *Screen) setColor(mode string, c color.Color) {
+	switch mode {
+	case "38":
+		s.currentFG = c
+	case "48":
+		s.currentBG = c
+	case "58":
+		s.underlineColor = c
 	}
 }
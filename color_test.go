@@ -7,7 +7,6 @@ import (
 	"testing"
 
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/widget"
 	widget2 "github.com/fyne-io/terminal/internal/widget"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,18 +25,19 @@ func testColor(t *testing.T, tests map[string]struct {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			terminal := New()
-			terminal.handleOutput([]byte(test.inputSeq))
+			terminal.screen.handleOutput([]byte(test.inputSeq))
 
-			// Verify the actual results match the expected results
-			if !reflect.DeepEqual(terminal.currentFG, test.expectedFg) {
-				t.Errorf("Foreground color mismatch. Got %v, expected %v", terminal.currentFG, test.expectedFg)
+			// Verify the actual results match the expected results, looking up palette colours
+			fg, bg := widget2.ResolveColor(terminal.screen.currentFG), widget2.ResolveColor(terminal.screen.currentBG)
+			if !reflect.DeepEqual(fg, test.expectedFg) {
+				t.Errorf("Foreground color mismatch. Got %v, expected %v", fg, test.expectedFg)
 			}
 
-			if !reflect.DeepEqual(terminal.currentBG, test.expectedBg) {
-				t.Errorf("Background color mismatch. Got %v, expected %v", terminal.currentBG, test.expectedBg)
+			if !reflect.DeepEqual(bg, test.expectedBg) {
+				t.Errorf("Background color mismatch. Got %v, expected %v", bg, test.expectedBg)
 			}
-			if terminal.bold != test.expectedBold {
-				t.Errorf("Bold flag mismatch. Got %v, expected %v", terminal.bold, test.expectedBold)
+			if terminal.screen.bold != test.expectedBold {
+				t.Errorf("Bold flag mismatch. Got %v, expected %v", terminal.screen.bold, test.expectedBold)
 			}
 		})
 	}
@@ -58,10 +58,10 @@ func TestHandleOutput_Text(t *testing.T) {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			terminal := New()
-			terminal.handleOutput([]byte(test.inputSeq))
+			terminal.screen.handleOutput([]byte(test.inputSeq))
 
-			if terminal.bold != test.expectBold {
-				t.Errorf("Bold flag mismatch. Got %v, expected %v", terminal.bold, test.expectBold)
+			if terminal.screen.bold != test.expectBold {
+				t.Errorf("Bold flag mismatch. Got %v, expected %v", terminal.screen.bold, test.expectBold)
 			}
 		})
 	}
@@ -76,14 +76,14 @@ func TestHandleOutput_Normal_Text(t *testing.T) {
 	}{
 		"reverse video": {
 			inputSeq:     esc("[7m"),
-			expectedFg:   color.NRGBA{R: 34, G: 34, B: 34, A: 255},
-			expectedBg:   color.NRGBA{R: 255, G: 255, B: 255, A: 255},
+			expectedFg:   nil,
+			expectedBg:   nil,
 			expectedBold: false,
 		},
 		"reverse video and bold": {
 			inputSeq:     esc("[7m") + esc("[1m"),
-			expectedFg:   color.NRGBA{R: 34, G: 34, B: 34, A: 255},
-			expectedBg:   color.NRGBA{R: 255, G: 255, B: 255, A: 255},
+			expectedFg:   nil,
+			expectedBg:   nil,
 			expectedBold: true,
 		},
 		"reverse video and bold then reset": {
@@ -97,6 +97,63 @@ func TestHandleOutput_Normal_Text(t *testing.T) {
 	testColor(t, tests)
 }
 
+func TestHandleOutput_Attributes(t *testing.T) {
+	s := NewScreen(20, 1, nil)
+	_, _ = s.Write([]byte(esc("[1;3;4;9ma") + esc("[22;23;24;29mb") + esc("[2;7;31mc") + esc("[8md") + esc("[0me")))
+
+	a := s.Cell(0, 0).Style
+	assert.Equal(t, fyne.TextStyle{Bold: true, Italic: true, Underline: true, Strikethrough: true}, a.Style())
+	assert.Equal(t, fyne.TextStyle{}, s.Cell(0, 1).Style.Style())
+
+	c := s.Cell(0, 2).Style.(*widget2.TermTextGridStyle)
+	assert.True(t, c.Dim)
+	assert.True(t, c.Reversed)
+	assert.Equal(t, basicColors[1], c.BackgroundColor())
+	assert.NotEqual(t, basicColors[1], c.TextColor())
+
+	d := s.Cell(0, 3).Style.(*widget2.TermTextGridStyle)
+	assert.True(t, d.Invisible)
+	assert.Equal(t, d.BackgroundColor(), d.TextColor())
+
+	assert.Nil(t, s.Cell(0, 4).Style.TextColor())
+	assert.False(t, s.bold || s.dim || s.inverse || s.invisible)
+}
+
+func TestHandleOutput_Underline(t *testing.T) {
+	s := NewScreen(20, 1, nil)
+	_, _ = s.Write([]byte(esc("[4ma") + esc("[4:3mb") + esc("[58:2::255:0:0mc") + esc("[21;58;5;2md") +
+		esc("[4:0;59me") + esc("[38:2:1:2:3mf")))
+
+	assert.True(t, s.Cell(0, 0).Style.Style().Underline)
+
+	b := s.Cell(0, 1).Style.(*widget2.TermTextGridStyle)
+	assert.False(t, b.Style().Underline)
+	assert.Equal(t, widget2.UnderlineCurly, b.UnderlineStyle)
+	assert.Nil(t, b.UnderlineColor)
+
+	c := s.Cell(0, 2).Style.(*widget2.TermTextGridStyle)
+	assert.Equal(t, widget2.UnderlineCurly, c.UnderlineStyle)
+	assert.Equal(t, &color.RGBA{255, 0, 0, 255}, c.UnderlineColor)
+
+	d := s.Cell(0, 3).Style.(*widget2.TermTextGridStyle)
+	assert.Equal(t, widget2.UnderlineDouble, d.UnderlineStyle)
+	assert.Equal(t, basicColors[2], widget2.ResolveColor(d.UnderlineColor))
+
+	e := s.Cell(0, 4).Style
+	assert.False(t, e.Style().Underline)
+	_, ok := e.(*widget2.TermTextGridStyle)
+	assert.False(t, ok)
+
+	assert.Equal(t, &color.RGBA{1, 2, 3, 255}, s.Cell(0, 5).Style.TextColor())
+}
+
+func TestHandleOutput_BoldBright(t *testing.T) {
+	s := NewScreen(10, 1, nil)
+	_, _ = s.Write([]byte(esc("[1;32mx")))
+
+	assert.Equal(t, brightColors[2], s.Cell(0, 0).Style.TextColor())
+}
+
 func TestHandleOutput_ANSI_Colors(t *testing.T) {
 	tests := map[string]struct {
 		inputSeq     string
@@ -142,7 +199,7 @@ func TestHandleOutput_ANSI_Colors(t *testing.T) {
 		},
 		"[36m": {
 			inputSeq:     esc("[36m"),
-			expectedFg:   &color.RGBA{0, 255, 255, 255},
+			expectedFg:   &color.RGBA{0, 170, 170, 255},
 			expectedBg:   nil,
 			expectedBold: false,
 		},
@@ -161,7 +218,7 @@ func TestHandleOutput_ANSI_Colors(t *testing.T) {
 		"reverse video": {
 			inputSeq:     esc("[7m") + esc("[37m"),
 			expectedFg:   &color.RGBA{170, 170, 170, 255},
-			expectedBg:   color.NRGBA{255, 255, 255, 255},
+			expectedBg:   nil,
 			expectedBold: false,
 		},
 	}
@@ -623,7 +680,7 @@ func TestHandleOutput_xterm_256_1(t *testing.T) {
 		},
 		"[36;48;5;16m": {
 			inputSeq:     esc("[36;48;5;16m"),
-			expectedFg:   &color.RGBA{0, 255, 255, 255},
+			expectedFg:   &color.RGBA{0, 170, 170, 255},
 			expectedBg:   &color.RGBA{0, 0, 0, 255},
 			expectedBold: false,
 		},
@@ -707,7 +764,7 @@ func TestHandleOutput_xterm_256_2(t *testing.T) {
 		},
 		"[38;5;6m": {
 			inputSeq:     esc("[38;5;6m"),
-			expectedFg:   &color.RGBA{0, 255, 255, 255},
+			expectedFg:   &color.RGBA{0, 170, 170, 255},
 			expectedBg:   nil,
 			expectedBold: false,
 		},
@@ -912,21 +969,13 @@ func TestHandleOutput_BufferCutoff(t *testing.T) {
 	term := New()
 	termsize := fyne.NewSize(80, 50)
 	term.Resize(termsize)
-	term.handleOutput([]byte("\x1b[38;5;64"))
-	term.handleOutput([]byte("m40\x1b[38;5;65m41"))
-	tg := widget2.NewTermGrid()
-	tg.Resize(termsize)
+	term.screen.handleOutput([]byte("\x1b[38;5;64"))
+	term.screen.handleOutput([]byte("m40\x1b[38;5;65m41"))
 	c1 := &color.RGBA{R: 95, G: 135, A: 255}
 	c2 := &color.RGBA{R: 95, G: 135, B: 95, A: 255}
-	tg.Rows = []widget.TextGridRow{
-		{
-			Cells: []widget.TextGridCell{
-				{Rune: '4', Style: &widget.CustomTextGridStyle{FGColor: c1, BGColor: nil}},
-				{Rune: '0', Style: &widget.CustomTextGridStyle{FGColor: c1, BGColor: nil}},
-				{Rune: '4', Style: &widget.CustomTextGridStyle{FGColor: c2, BGColor: nil}},
-				{Rune: '1', Style: &widget.CustomTextGridStyle{FGColor: c2, BGColor: nil}},
-			},
-		},
+	assert.Equal(t, "4041", term.screen.content.Text())
+	for i, c := range []color.Color{c1, c1, c2, c2} {
+		assert.Equal(t, c, term.screen.Cell(0, i).Style.TextColor())
+		assert.Nil(t, term.screen.Cell(0, i).Style.BackgroundColor())
 	}
-	assert.Equal(t, tg.Rows, term.content.Rows)
 }
@@ -0,0 +1,233 @@
+package terminal
+
+import (
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Command is a command line that was run in the shell, found using the OSC 133 marks of shell integration.
+// Rows count lines from the oldest line of history followed by the rows of the screen, like SearchMatch.
+// Rows that have been dropped from the history are negative.
+type Command struct {
+	// Text is the command line that the user entered after the prompt.
+	Text string
+	// PromptRow is the row where the prompt starts.
+	PromptRow int
+	// OutputStartRow and OutputEndRow are the first and last rows of output.
+	// OutputEndRow is less than OutputStartRow if there was no output.
+	OutputStartRow, OutputEndRow int
+	// ExitCode is the status reported by the shell, or -1 if it is still running or none was reported.
+	ExitCode int
+	// Finished is false while the command is still running.
+	Finished bool
+}
+
+// shellCommand is the marks of one prompt and the command entered at it.
+// Lines are numbered from the first row ever pushed to the history, so they do not change as output scrolls.
+type shellCommand struct {
+	prompt, input, output, end int
+	inputCol                   int
+	text                       string
+	exitCode                   int
+	hasInput, ran, finished    bool
+}
+
+// Commands returns the commands that have been run in the shell, oldest first.
+// These are only known if the shell uses OSC 133 to mark its prompts, as the shell integration of many shells can.
+func (s *Screen) Commands() []Command {
+	first := s.scrollback.firstLine()
+	var cmds []Command
+	for _, c := range s.commands {
+		if !c.ran {
+			continue
+		}
+
+		end := c.end
+		if !c.finished {
+			end = s.outputEnd()
+		}
+		cmds = append(cmds, c.command(first, end))
+	}
+	return cmds
+}
+
+// ScrollToPreviousPrompt scrolls the history back so the prompt above the top of the view is at the top.
+// It returns false if there is no earlier prompt in the history.
+func (t *Terminal) ScrollToPreviousPrompt() bool {
+	if t.screen.altBufferActive {
+		return false
+	}
+
+	first, top := t.screen.scrollback.firstLine(), t.viewTopLine()
+	for i := len(t.screen.commands) - 1; i >= 0; i-- {
+		line := t.screen.commands[i].prompt
+		if line < top && line >= first {
+			t.scrollHistory(top - line)
+			return true
+		}
+	}
+	return false
+}
+
+// ScrollToNextPrompt scrolls the history forward so the prompt below the top of the view is at the top,
+// or as near to the top as the end of the output allows.
+// It returns false if there is no later prompt or the view is already showing the screen.
+func (t *Terminal) ScrollToNextPrompt() bool {
+	if t.screen.altBufferActive || t.scrollOffset == 0 {
+		return false
+	}
+
+	top := t.viewTopLine()
+	for _, c := range t.screen.commands {
+		if c.prompt > top {
+			t.scrollHistory(top - c.prompt)
+			return true
+		}
+	}
+	return false
+}
+
+// viewTopLine returns the line number of the top row of the view.
+func (t *Terminal) viewTopLine() int {
+	return t.screen.scrollback.nextLine() - t.scrollOffset
+}
+
+// handleOSCShellIntegration records the FinalTerm marks sent by a shell: "A" at the start of the prompt,
+// "B" where the command line starts, "C" when the command starts running and "D;<exit code>" when it finishes.
+func (s *Screen) handleOSCShellIntegration(data string) {
+	mark, params := data, ""
+	if i := strings.IndexByte(data, ';'); i >= 0 {
+		mark, params = data[:i], data[i+1:]
+	}
+
+	var last *shellCommand
+	if len(s.commands) > 0 {
+		last = s.commands[len(s.commands)-1]
+	}
+	switch mark {
+	case "A":
+		if last != nil && !last.finished {
+			s.finishCommand(last, -1)
+		}
+		s.pruneCommands()
+		s.commands = append(s.commands, &shellCommand{prompt: s.cursorLine(), exitCode: -1})
+	case "B":
+		if last != nil && !last.ran {
+			last.input, last.inputCol, last.hasInput = s.cursorLine(), s.cursorCol, true
+		}
+	case "C":
+		if last == nil || last.ran {
+			return
+		}
+		last.ran, last.output = true, s.cursorLine()
+		if last.hasInput {
+			last.text = s.textBetween(last.input, last.inputCol, last.output, s.cursorCol)
+		}
+	case "D":
+		if last == nil || last.finished {
+			return
+		}
+		code := -1
+		if params != "" {
+			if i := strings.IndexByte(params, ';'); i >= 0 {
+				params = params[:i]
+			}
+			if c, err := strconv.Atoi(params); err == nil {
+				code = c
+			}
+		}
+		s.finishCommand(last, code)
+	default:
+		if s.debug {
+			log.Println("Unrecognised shell integration mark", data)
+		}
+	}
+}
+
+func (s *Screen) finishCommand(c *shellCommand, exitCode int) {
+	c.finished, c.end, c.exitCode = true, s.outputEnd(), exitCode
+	if c.ran && s.commandFinished != nil {
+		s.commandFinished(c.command(s.scrollback.firstLine(), c.end))
+	}
+}
+
+func (t *Terminal) finishedCommand(c Command) {
+	if t.OnCommandFinished != nil {
+		t.OnCommandFinished(c)
+	}
+}
+
+// command returns the public view of the command, with rows counted from the given first line of history.
+func (c *shellCommand) command(first, end int) Command {
+	return Command{Text: c.text, PromptRow: c.prompt - first, OutputStartRow: c.output - first,
+		OutputEndRow: end - first, ExitCode: c.exitCode, Finished: c.finished}
+}
+
+// pruneCommands forgets the commands that have scrolled out of the history.
+func (s *Screen) pruneCommands() {
+	first := s.scrollback.firstLine()
+	keep := 0
+	for keep < len(s.commands) && s.commands[keep].finished && s.commands[keep].end < first {
+		keep++
+	}
+	s.commands = s.commands[keep:]
+}
+
+// commandLines returns pointers to all of the line numbers of the commands, so they can be updated on reflow.
+func (s *Screen) commandLines() []*int {
+	lines := make([]*int, 0, len(s.commands)*4)
+	for _, c := range s.commands {
+		lines = append(lines, &c.prompt, &c.input, &c.output, &c.end)
+	}
+	return lines
+}
+
+func (s *Screen) cursorLine() int {
+	return s.scrollback.nextLine() + s.cursorRow
+}
+
+// outputEnd returns the last line of output so far, which is above the cursor if it is at the start of a line.
+func (s *Screen) outputEnd() int {
+	if s.cursorCol == 0 {
+		return s.cursorLine() - 1
+	}
+	return s.cursorLine()
+}
+
+// lineRow returns the row of history or the screen with the given line number.
+func (s *Screen) lineRow(line int) (row []rune, wrapped bool) {
+	i := line - s.scrollback.firstLine()
+	if i < 0 {
+		return nil, false
+	}
+	if i < s.scrollback.len() {
+		return rowRunes(s.scrollback.row(i)), s.scrollback.isWrapped(i)
+	}
+	i -= s.scrollback.len()
+	return rowRunes(s.content.Row(i)), s.content.isWrapped(i)
+}
+
+// textBetween returns the text from one position to another, joining rows that were wrapped by the terminal.
+func (s *Screen) textBetween(startLine, startCol, endLine, endCol int) string {
+	var text strings.Builder
+	for line := startLine; line <= endLine; line++ {
+		row, wrapped := s.lineRow(line)
+		from, to := 0, len(row)
+		if line == startLine && startCol < to {
+			from = startCol
+		} else if line == startLine {
+			from = to
+		}
+		if line == endLine && endCol < to {
+			to = endCol
+		}
+		if from < to {
+			text.WriteString(strings.TrimRight(string(row[from:to]), " "))
+		}
+		if !wrapped && line < endLine {
+			text.WriteByte('\n')
+		}
+	}
+	return strings.TrimSpace(text.String())
+}
@@ -0,0 +1,73 @@
+package terminal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreen_Commands(t *testing.T) {
+	s := NewScreen(10, 4, nil)
+	_, _ = s.Write([]byte("\x1b]133;A\x07$ \x1b]133;B\x07ls -l\r\n\x1b]133;C\x07a\r\nb\r\n\x1b]133;D;2\x07"))
+	_, _ = s.Write([]byte("\x1b]133;A\x07$ \x1b]133;B\x07echo a long line\r\n\x1b]133;C\x07"))
+
+	cmds := s.Commands()
+	assert.Equal(t, 2, len(cmds))
+	assert.Equal(t, Command{Text: "ls -l", PromptRow: 0, OutputStartRow: 1, OutputEndRow: 2, ExitCode: 2, Finished: true}, cmds[0])
+	assert.Equal(t, Command{Text: "echo a long line", PromptRow: 3, OutputStartRow: 5, OutputEndRow: 4, ExitCode: -1}, cmds[1])
+
+	_, _ = s.Write([]byte("x\x1b]133;A\x07$ \x1b]133;D\x07"))
+	cmds = s.Commands()
+	assert.Equal(t, 2, len(cmds))
+	assert.Equal(t, Command{Text: "echo a long line", PromptRow: 3, OutputStartRow: 5, OutputEndRow: 5, ExitCode: -1, Finished: true}, cmds[1])
+}
+
+func TestScreen_CommandsReflow(t *testing.T) {
+	s := NewScreen(10, 4, nil)
+	_, _ = s.Write([]byte("0123456789ab\r\n\x1b]133;A\x07$ \x1b]133;B\x07cmd\r\n\x1b]133;C\x07\x1b]133;D;0\x07"))
+	assert.Equal(t, 2, s.Commands()[0].PromptRow)
+
+	s.SetSize(20, 4)
+	assert.Equal(t, 1, s.Commands()[0].PromptRow)
+	assert.Equal(t, 2, s.Commands()[0].OutputStartRow)
+}
+
+func TestTerminal_CommandFinished(t *testing.T) {
+	term := New()
+	term.screen.SetSize(10, 2)
+	var finished []Command
+	term.OnCommandFinished = func(c Command) {
+		finished = append(finished, c)
+	}
+
+	_, _ = term.screen.Write([]byte("\x1b]133;A\x07$ \x1b]133;B\x07true\r\n\x1b]133;C\x07\x1b]133;D;0\x07"))
+	_, _ = term.screen.Write([]byte("\x1b]133;A\x07$ \x1b]133;D\x07"))
+	assert.Equal(t, 1, len(finished))
+	assert.Equal(t, "true", finished[0].Text)
+	assert.Equal(t, 0, finished[0].ExitCode)
+}
+
+func TestTerminal_ScrollToPrompt(t *testing.T) {
+	term := New()
+	term.screen.SetSize(10, 2)
+	for i := 0; i < 3; i++ {
+		_, _ = term.screen.Write([]byte("\x1b]133;A\x07$ \x1b]133;B\x07cmd\r\n\x1b]133;C\x07out\r\n\x1b]133;D;0\x07"))
+	}
+	_, _ = term.screen.Write([]byte("\x1b]133;A\x07$ "))
+	assert.Equal(t, 5, term.screen.scrollback.len())
+
+	assert.False(t, term.ScrollToNextPrompt())
+	assert.True(t, term.ScrollToPreviousPrompt())
+	assert.Equal(t, 1, term.scrollOffset)
+	assert.True(t, term.ScrollToPreviousPrompt())
+	assert.True(t, term.ScrollToPreviousPrompt())
+	assert.Equal(t, 5, term.scrollOffset)
+	assert.False(t, term.ScrollToPreviousPrompt())
+
+	assert.True(t, term.ScrollToNextPrompt())
+	assert.Equal(t, 3, term.scrollOffset)
+	assert.True(t, term.ScrollToNextPrompt())
+	assert.True(t, term.ScrollToNextPrompt())
+	assert.Equal(t, 0, term.scrollOffset)
+	assert.False(t, term.ScrollToNextPrompt())
+}
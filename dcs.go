@@ -3,20 +3,24 @@ package terminal
 import (
 	"encoding/hex"
 	"log"
+	"strings"
 )
 
-func (t *Terminal) handleDCS(code string) {
-	if len(code) >= 2 && code[:2] == "+q" {
+func (s *Screen) handleDCS(code string) {
+	data := strings.TrimLeft(code, "0123456789;")
+	if len(data) > 0 && data[0] == 'q' {
+		s.handleSixel(code[:len(code)-len(data)], data[1:])
+	} else if len(code) >= 2 && code[:2] == "+q" {
 		query, _ := hex.DecodeString(code[2:]) // strip the +q
-		if t.debug {
+		if s.debug {
 			log.Println("unhandled DCS query", query)
 		}
 
-		_, _ = t.in.Write([]byte{asciiEscape})
-		_, _ = t.in.Write([]byte("P0+r")) // return not recognised - TODO actually return results
-		_, _ = t.in.Write([]byte{asciiEscape, '\\', 0})
+		_, _ = s.reply.Write([]byte{asciiEscape})
+		_, _ = s.reply.Write([]byte("P0+r")) // return not recognised - TODO actually return results
+		_, _ = s.reply.Write([]byte{asciiEscape, '\\', 0})
 	} else {
-		if t.debug {
+		if s.debug {
 			log.Println("unknown DCS query", code)
 		}
 	}
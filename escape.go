@@ -7,9 +7,10 @@ import (
 	"strings"
 
 	"fyne.io/fyne/v2/widget"
+	widget2 "github.com/fyne-io/terminal/internal/widget"
 )
 
-var escapes = map[rune]func(*Terminal, string){
+var escapes = map[rune]func(*Screen, string){
 	'@': escapeInsertChars,
 	'A': escapeMoveCursorUp,
 	'B': escapeMoveCursorDown,
@@ -39,7 +40,7 @@ var escapes = map[rune]func(*Terminal, string){
 	't': escapeWindowOps,
 }
 
-func (t *Terminal) handleEscape(code string) {
+func (s *Screen) handleEscape(code string) {
 	code = trimLeftZeros(code)
 	if code == "" {
 		return
@@ -47,268 +48,284 @@ func (t *Terminal) handleEscape(code string) {
 
 	runes := []rune(code)
 	if esc, ok := escapes[runes[len(code)-1]]; ok {
-		esc(t, code[:len(code)-1])
-	} else if t.debug {
+		esc(s, code[:len(code)-1])
+	} else if s.debug {
 		log.Println("Unrecognised Escape:", strconv.QuoteToASCII(code))
 	}
 }
 
 // enterAltBuffer saves the current screen and cursor, clears the display,
 // and enters the alternate screen buffer (used by curses apps).
-func (t *Terminal) enterAltBuffer() {
-	if t.altBufferActive {
+func (s *Screen) enterAltBuffer() {
+	if s.altBufferActive {
 		return
 	}
 	// Save current grid content (deep copy)
-	t.altSavedGrid = make([]widget.TextGridRow, len(t.content.Rows))
-	for i, row := range t.content.Rows {
+	s.altSavedGrid = make([]widget.TextGridRow, len(s.content.Rows))
+	for i, row := range s.content.Rows {
 		cells := make([]widget.TextGridCell, len(row.Cells))
 		copy(cells, row.Cells)
-		t.altSavedGrid[i] = widget.TextGridRow{Cells: cells}
+		s.altSavedGrid[i] = widget.TextGridRow{Cells: cells}
 	}
-	t.altSavedRow = t.cursorRow
-	t.altSavedCol = t.cursorCol
-	t.altBufferActive = true
-	t.clearScreen()
+	s.altSavedWrapped = append([]bool(nil), s.content.wrapped...)
+	s.altSavedRow = s.cursorRow
+	s.altSavedCol = s.cursorCol
+	s.altBufferActive = true
+	if s.historyReset != nil {
+		s.historyReset()
+	}
+	s.clearScreen()
 }
 
 // exitAltBuffer restores the saved screen and cursor from before
 // enterAltBuffer was called.
-func (t *Terminal) exitAltBuffer() {
-	if !t.altBufferActive {
+func (s *Screen) exitAltBuffer() {
+	if !s.altBufferActive {
 		return
 	}
-	t.altBufferActive = false
-	if t.altSavedGrid != nil {
+	s.altBufferActive = false
+	if s.altSavedGrid != nil {
 		// Restore saved grid content
-		for i, row := range t.altSavedGrid {
-			if i < len(t.content.Rows) {
-				t.content.SetRow(i, row)
+		for i, row := range s.altSavedGrid {
+			if i < len(s.content.Rows) {
+				s.content.SetRow(i, row)
+				s.content.setWrapped(i, i < len(s.altSavedWrapped) && s.altSavedWrapped[i])
 			}
 		}
 		// Clear any extra rows beyond the saved content
-		for i := len(t.altSavedGrid); i < len(t.content.Rows); i++ {
-			t.content.SetRow(i, widget.TextGridRow{})
+		for i := len(s.altSavedGrid); i < len(s.content.Rows); i++ {
+			s.content.SetRow(i, widget.TextGridRow{})
 		}
-		t.altSavedGrid = nil
+		s.altSavedGrid, s.altSavedWrapped = nil, nil
 	}
-	t.cursorRow = t.altSavedRow
-	t.cursorCol = t.altSavedCol
+	s.cursorRow = s.altSavedRow
+	s.cursorCol = s.altSavedCol
 }
 
-func (t *Terminal) clearScreen() {
-	t.moveCursor(0, 0)
-	t.clearScreenFromCursor()
+func (s *Screen) clearScreen() {
+	s.moveCursor(0, 0)
+	s.clearScreenFromCursor()
 }
 
-func (t *Terminal) clearScreenFromCursor() {
-	row := t.content.Row(t.cursorRow)
-	from := t.cursorCol
-	if t.cursorCol > len(row.Cells) {
+func (s *Screen) clearScreenFromCursor() {
+	row := s.content.Row(s.cursorRow)
+	from := s.cursorCol
+	if s.cursorCol > len(row.Cells) {
 		from = len(row.Cells)
 	}
 	if from > 0 {
-		t.content.SetRow(t.cursorRow, widget.TextGridRow{Cells: row.Cells[:from]})
+		s.content.SetRow(s.cursorRow, widget.TextGridRow{Cells: row.Cells[:from]})
 	} else {
-		t.content.SetRow(t.cursorRow, widget.TextGridRow{})
+		s.content.SetRow(s.cursorRow, widget.TextGridRow{})
 	}
 
-	for i := t.cursorRow + 1; i < len(t.content.Rows); i++ {
-		t.content.SetRow(i, widget.TextGridRow{})
+	for i := s.cursorRow + 1; i < len(s.content.Rows); i++ {
+		s.content.SetRow(i, widget.TextGridRow{})
 	}
 }
 
-func (t *Terminal) clearScreenToCursor() {
-	row := t.content.Row(t.cursorRow)
-	cells := make([]widget.TextGridCell, t.cursorCol)
-	if t.cursorCol < len(row.Cells) {
-		cells = append(cells, row.Cells[t.cursorCol:]...)
+func (s *Screen) clearScreenToCursor() {
+	row := s.content.Row(s.cursorRow)
+	cells := make([]widget.TextGridCell, s.cursorCol)
+	if s.cursorCol < len(row.Cells) {
+		cells = append(cells, row.Cells[s.cursorCol:]...)
 	}
 
-	t.content.SetRow(t.cursorRow, widget.TextGridRow{Cells: cells})
+	s.content.SetRow(s.cursorRow, widget.TextGridRow{Cells: cells})
 
-	for i := 0; i < t.cursorRow-1; i++ {
-		t.content.SetRow(i, widget.TextGridRow{})
+	for i := 0; i < s.cursorRow-1; i++ {
+		s.content.SetRow(i, widget.TextGridRow{})
 	}
 }
 
-func (t *Terminal) handleVT100(code string) {
+func (s *Screen) handleVT100(code string) {
 	switch code {
 	case "(A":
-		t.g0Charset = charSetAlternate
+		s.g0Charset = charSetAlternate
 	case ")A":
-		t.g1Charset = charSetAlternate
+		s.g1Charset = charSetAlternate
 	case "(B":
-		t.g0Charset = charSetANSII
+		s.g0Charset = charSetANSII
 	case ")B":
-		t.g1Charset = charSetANSII
+		s.g1Charset = charSetANSII
 	case "(0":
-		t.g0Charset = charSetDECSpecialGraphics
+		s.g0Charset = charSetDECSpecialGraphics
 	case ")0":
-		t.g1Charset = charSetDECSpecialGraphics
+		s.g1Charset = charSetDECSpecialGraphics
 	default:
-		if t.debug {
+		if s.debug {
 			log.Println("Unhandled VT100:", code)
 		}
 	}
 }
 
-func (t *Terminal) moveCursor(row, col int) {
-	if t.config.Columns == 0 || t.config.Rows == 0 {
+func (s *Screen) moveCursor(row, col int) {
+	if s.config.Columns == 0 || s.config.Rows == 0 {
 		return
 	}
 	if col < 0 {
 		col = 0
-	} else if col >= int(t.config.Columns) {
-		col = int(t.config.Columns) - 1
+	} else if col >= int(s.config.Columns) {
+		col = int(s.config.Columns) - 1
 	}
 
 	if row < 0 {
 		row = 0
-	} else if row >= int(t.config.Rows) {
-		row = int(t.config.Rows) - 1
+	} else if row >= int(s.config.Rows) {
+		row = int(s.config.Rows) - 1
 	}
 
-	t.cursorCol = col
-	t.cursorRow = row
+	s.cursorCol = col
+	s.cursorRow = row
 
-	if t.cursorMoved != nil {
-		t.cursorMoved()
+	if s.cursorMoved != nil {
+		s.cursorMoved()
 	}
 }
 
-func escapeColorMode(t *Terminal, msg string) {
-	t.handleColorEscape(msg)
+func escapeColorMode(s *Screen, msg string) {
+	if msg != "" && (msg[0] == '>' || msg[0] == '?') {
+		s.handleModifyKeys(msg)
+		return
+	}
+	s.handleColorEscape(msg)
 }
 
-func escapeDeleteChars(t *Terminal, msg string) {
+func escapeDeleteChars(s *Screen, msg string) {
 	i, _ := strconv.Atoi(msg)
 	if i == 0 {
 		i = 1
 	}
-	right := t.cursorCol + i
+	right := s.cursorCol + i
 
-	row := t.content.Row(t.cursorRow)
-	cells := row.Cells[:t.cursorCol]
+	row := s.content.Row(s.cursorRow)
+	cells := row.Cells[:s.cursorCol]
 	if right < len(row.Cells) {
 		cells = append(cells, row.Cells[right:]...)
 	}
 
-	t.content.SetRow(t.cursorRow, widget.TextGridRow{Cells: cells})
+	s.content.SetRow(s.cursorRow, widget.TextGridRow{Cells: cells})
 }
 
 // escapeEraseChars handles CSI Ps X (ECH - Erase Character).
 // Replaces Ps characters starting at cursor with blanks.
-func escapeEraseChars(t *Terminal, msg string) {
+func escapeEraseChars(s *Screen, msg string) {
 	count, _ := strconv.Atoi(msg)
 	if count == 0 {
 		count = 1
 	}
 
-	row := t.content.Row(t.cursorRow)
-	cellStyle := &widget.CustomTextGridStyle{FGColor: t.currentFG, BGColor: t.currentBG}
+	row := s.content.Row(s.cursorRow)
+	cellStyle := &widget2.CellStyle{FGColor: s.currentFG, BGColor: s.currentBG, Palette: s.palette}
 	// Extend row if cursor is beyond current length
-	for len(row.Cells) < t.cursorCol+count {
+	for len(row.Cells) < s.cursorCol+count {
 		row.Cells = append(row.Cells, widget.TextGridCell{Rune: ' ', Style: cellStyle})
 	}
 	for i := 0; i < count; i++ {
-		row.Cells[t.cursorCol+i] = widget.TextGridCell{Rune: ' ', Style: cellStyle}
+		row.Cells[s.cursorCol+i] = widget.TextGridCell{Rune: ' ', Style: cellStyle}
 	}
-	t.content.SetRow(t.cursorRow, row)
+	wrapped := s.content.isWrapped(s.cursorRow)
+	s.content.SetRow(s.cursorRow, row)
+	s.content.setWrapped(s.cursorRow, wrapped)
 }
 
 // escapeDeleteLines handles CSI Ps M (DL - Delete Line).
 // Deletes Ps lines at cursor, scrolling lines below up within the scroll region.
-func escapeDeleteLines(t *Terminal, msg string) {
+func escapeDeleteLines(s *Screen, msg string) {
 	lines, _ := strconv.Atoi(msg)
 	if lines == 0 {
 		lines = 1
 	}
-	for i := t.cursorRow; i <= t.scrollBottom-lines; i++ {
-		t.content.SetRow(i, t.content.Row(i+lines))
+	for i := s.cursorRow; i <= s.scrollBottom-lines; i++ {
+		s.content.copyRow(i, i+lines)
 	}
-	for i := t.scrollBottom - lines + 1; i <= t.scrollBottom; i++ {
-		t.content.SetRow(i, widget.TextGridRow{})
+	for i := s.scrollBottom - lines + 1; i <= s.scrollBottom; i++ {
+		s.content.SetRow(i, widget.TextGridRow{})
 	}
 }
 
 // escapeScrollDown handles CSI Ps T (SD - Scroll Down).
 // Scrolls the scroll region down by Ps lines, inserting blank lines at the top.
-func escapeScrollDown(t *Terminal, msg string) {
+func escapeScrollDown(s *Screen, msg string) {
 	lines, _ := strconv.Atoi(msg)
 	if lines == 0 {
 		lines = 1
 	}
-	for i := t.scrollBottom; i >= t.scrollTop+lines; i-- {
-		t.content.SetRow(i, t.content.Row(i-lines))
+	for i := s.scrollBottom; i >= s.scrollTop+lines; i-- {
+		s.content.copyRow(i, i-lines)
 	}
-	for i := t.scrollTop; i < t.scrollTop+lines && i <= t.scrollBottom; i++ {
-		t.content.SetRow(i, widget.TextGridRow{})
+	for i := s.scrollTop; i < s.scrollTop+lines && i <= s.scrollBottom; i++ {
+		s.content.SetRow(i, widget.TextGridRow{})
 	}
 }
 
 // escapeRepeatChar handles CSI Ps b (REP - Repeat).
 // Repeats the preceding graphic character Ps times.
-func escapeRepeatChar(t *Terminal, msg string) {
+func escapeRepeatChar(s *Screen, msg string) {
 	count, _ := strconv.Atoi(msg)
 	if count == 0 {
 		count = 1
 	}
-	if t.lastChar == 0 {
+	if s.lastChar == 0 {
 		return
 	}
 	for i := 0; i < count; i++ {
-		t.handleOutputChar(t.lastChar)
+		s.handleOutputChar(s.lastChar)
 	}
 }
 
 // escapeWindowOps handles CSI Ps;..t (window operations).
 // Most are queries or title save/restore — safe to ignore.
-func escapeWindowOps(_ *Terminal, _ string) {
+func escapeWindowOps(_ *Screen, _ string) {
 	// no-op: title save/restore, window resize queries, etc.
 }
 
-func escapeEraseInLine(t *Terminal, msg string) {
+func escapeEraseInLine(s *Screen, msg string) {
 	mode, _ := strconv.Atoi(msg)
 	switch mode {
 	case 0:
-		row := t.content.Row(t.cursorRow)
-		if t.cursorCol >= len(row.Cells) {
+		row := s.content.Row(s.cursorRow)
+		if s.cursorCol >= len(row.Cells) {
 			return
 		}
-		t.content.SetRow(t.cursorRow, widget.TextGridRow{Cells: row.Cells[:t.cursorCol]})
+		s.content.SetRow(s.cursorRow, widget.TextGridRow{Cells: row.Cells[:s.cursorCol]})
 	case 1:
-		row := t.content.Row(t.cursorRow)
-		if t.cursorCol >= len(row.Cells) {
+		row := s.content.Row(s.cursorRow)
+		if s.cursorCol >= len(row.Cells) {
 			return
 		}
-		cells := make([]widget.TextGridCell, t.cursorCol)
-		t.content.SetRow(t.cursorRow, widget.TextGridRow{Cells: append(cells, row.Cells[t.cursorCol:]...)})
+		cells := make([]widget.TextGridCell, s.cursorCol)
+		s.content.SetRow(s.cursorRow, widget.TextGridRow{Cells: append(cells, row.Cells[s.cursorCol:]...)})
 	case 2:
-		t.content.SetRow(t.cursorRow, widget.TextGridRow{})
+		s.content.SetRow(s.cursorRow, widget.TextGridRow{})
 	}
 }
 
-func escapeEraseInScreen(t *Terminal, msg string) {
+func escapeEraseInScreen(s *Screen, msg string) {
 	mode, _ := strconv.Atoi(msg)
 	switch mode {
 	case 0:
-		t.clearScreenFromCursor()
+		s.clearScreenFromCursor()
 	case 1:
-		t.clearScreenToCursor()
+		s.clearScreenToCursor()
 	case 2:
-		t.clearScreen()
+		s.clearScreen()
+	case 3:
+		s.scrollback.clear()
+		if s.historyReset != nil {
+			s.historyReset()
+		}
 	}
 }
 
-func escapeInsertChars(t *Terminal, msg string) {
+func escapeInsertChars(s *Screen, msg string) {
 	chars, _ := strconv.Atoi(msg)
 	if chars == 0 {
 		chars = 1
 	}
 
 	newCells := make([]widget.TextGridCell, chars)
-	cellStyle := &widget.CustomTextGridStyle{FGColor: t.currentFG, BGColor: t.currentBG}
+	cellStyle := &widget2.CellStyle{FGColor: s.currentFG, BGColor: s.currentBG, Palette: s.palette}
 	for i := range newCells {
 		newCells[i] = widget.TextGridCell{
 			Rune:  ' ',
@@ -316,99 +333,125 @@ func escapeInsertChars(t *Terminal, msg string) {
 		}
 	}
 
-	row := &t.content.Rows[t.cursorRow]
-	row.Cells = append(row.Cells[:t.cursorCol], append(newCells, row.Cells[t.cursorCol:]...)...)
+	row := &s.content.Rows[s.cursorRow]
+	row.Cells = append(row.Cells[:s.cursorCol], append(newCells, row.Cells[s.cursorCol:]...)...)
+	s.content.markDirty(s.cursorRow)
 }
 
-func escapeInsertLines(t *Terminal, msg string) {
+func escapeInsertLines(s *Screen, msg string) {
 	rows, _ := strconv.Atoi(msg)
 	if rows == 0 {
 		rows = 1
 	}
-	i := t.scrollBottom
-	for ; i > t.cursorRow-rows+1; i-- {
-		t.content.SetRow(i, t.content.Row(i-rows))
+	i := s.scrollBottom
+	for ; i > s.cursorRow-rows+1; i-- {
+		s.content.copyRow(i, i-rows)
 	}
-	for ; i >= t.cursorRow; i-- {
-		t.content.SetRow(i, widget.TextGridRow{})
+	for ; i >= s.cursorRow; i-- {
+		s.content.SetRow(i, widget.TextGridRow{})
 	}
 }
 
-func escapeMoveCursorUp(t *Terminal, msg string) {
+func escapeMoveCursorUp(s *Screen, msg string) {
 	rows, _ := strconv.Atoi(msg)
 	if rows == 0 {
 		rows = 1
 	}
-	t.moveCursor(t.cursorRow-rows, t.cursorCol)
+	s.moveCursor(s.cursorRow-rows, s.cursorCol)
 }
 
-func escapeMoveCursorDown(t *Terminal, msg string) {
+func escapeMoveCursorDown(s *Screen, msg string) {
 	rows, _ := strconv.Atoi(msg)
 	if rows == 0 {
 		rows = 1
 	}
-	t.moveCursor(t.cursorRow+rows, t.cursorCol)
+	s.moveCursor(s.cursorRow+rows, s.cursorCol)
 }
 
-func escapeMoveCursorRight(t *Terminal, msg string) {
+func escapeMoveCursorRight(s *Screen, msg string) {
 	cols, _ := strconv.Atoi(msg)
 	if cols == 0 {
 		cols = 1
 	}
-	t.moveCursor(t.cursorRow, t.cursorCol+cols)
+	s.moveCursor(s.cursorRow, s.cursorCol+cols)
 }
 
-func escapeMoveCursorLeft(t *Terminal, msg string) {
+func escapeMoveCursorLeft(s *Screen, msg string) {
 	cols, _ := strconv.Atoi(msg)
 	if cols == 0 {
 		cols = 1
 	}
-	t.moveCursor(t.cursorRow, t.cursorCol-cols)
+	s.moveCursor(s.cursorRow, s.cursorCol-cols)
 }
 
-func escapeMoveCursorRow(t *Terminal, msg string) {
+func escapeMoveCursorRow(s *Screen, msg string) {
 	row, _ := strconv.Atoi(msg)
-	t.moveCursor(row-1, t.cursorCol)
+	s.moveCursor(row-1, s.cursorCol)
 }
 
-func escapeMoveCursorCol(t *Terminal, msg string) {
+func escapeMoveCursorCol(s *Screen, msg string) {
 	col, _ := strconv.Atoi(msg)
-	t.moveCursor(t.cursorRow, col-1)
+	s.moveCursor(s.cursorRow, col-1)
+}
+
+func (s *Screen) setMouseMode(mode mouseMode, enable bool) {
+	if enable {
+		s.mouseMode = mode
+	} else {
+		s.mouseMode = mouseModeOff
+	}
 }
 
-func escapePrivateMode(t *Terminal, msg string, enable bool) {
+// setMouseEncoding changes how mouse events are reported, turning one off only returns to the default
+// if it is the encoding in use.
+func (s *Screen) setMouseEncoding(encoding mouseEncoding, enable bool) {
+	if enable {
+		s.mouseEncoding = encoding
+	} else if s.mouseEncoding == encoding {
+		s.mouseEncoding = mouseEncodingDefault
+	}
+}
+
+func escapePrivateMode(s *Screen, msg string, enable bool) {
 	modes := strings.Split(msg, ";")
 	for _, mode := range modes {
 		switch mode {
 		case "7":
-			t.disableAutoWrap = !enable
+			s.disableAutoWrap = !enable
 		case "20":
-			t.newLineMode = enable
+			s.newLineMode = enable
 		case "25":
-			t.cursorHidden = !enable
-			t.refreshCursor()
+			s.cursorHidden = !enable
 		case "9":
-			if enable {
-				t.onMouseDown = t.handleMouseDownX10
-				t.onMouseUp = t.handleMouseUpX10
-			} else {
-				t.onMouseDown = nil
-				t.onMouseUp = nil
-			}
+			s.setMouseMode(mouseModeX10, enable)
 		case "1000":
-			if enable {
-				t.onMouseDown = t.handleMouseDownV200
-				t.onMouseUp = t.handleMouseUpV200
-			} else {
-				t.onMouseDown = nil
-				t.onMouseUp = nil
-			}
+			s.setMouseMode(mouseModeV200, enable)
+		case "1002":
+			s.setMouseMode(mouseModeButton, enable)
+		case "1003":
+			s.setMouseMode(mouseModeAny, enable)
+		case "1006":
+			s.setMouseEncoding(mouseEncodingSGR, enable)
+		case "1015":
+			s.setMouseEncoding(mouseEncodingURXVT, enable)
+		case "1016":
+			s.setMouseEncoding(mouseEncodingSGRPixels, enable)
+		case "1004":
+			s.focusReporting = enable
+		case "1034":
+			s.eightBitMeta = enable
+		case "1036", "1039":
+			s.disableAltEscape = !enable
+		case "1007":
+			s.disableAlternateScroll = !enable
+		case "66":
+			s.keypadApplication = enable
 		case "1049":
-			t.bufferMode = enable
+			s.bufferMode = enable
 			if enable {
-				t.enterAltBuffer()
+				s.enterAltBuffer()
 			} else {
-				t.exitAltBuffer()
+				s.exitAltBuffer()
 			}
 		case "1":
 			// DECCKM - cursor key mode (application vs normal)
@@ -416,12 +459,12 @@ func escapePrivateMode(t *Terminal, msg string, enable bool) {
 		case "12":
 			// ATT610 - cursor blink mode; no display impact
 		case "2004":
-			t.bracketedPasteMode = enable
+			s.bracketedPasteMode = enable
 		case "47":
 			if enable {
-				t.enterAltBuffer()
+				s.enterAltBuffer()
 			} else {
-				t.exitAltBuffer()
+				s.exitAltBuffer()
 			}
 		case "":
 			// empty mode, ignore
@@ -430,24 +473,24 @@ func escapePrivateMode(t *Terminal, msg string, enable bool) {
 			if enable {
 				m = "h"
 			}
-			if t.debug {
+			if s.debug {
 				log.Println("Unknown private escape code", fmt.Sprintf("?%s%s", mode, m))
 			}
 		}
 	}
 }
 
-func escapePrivateModeOff(t *Terminal, msg string) {
-	escapePrivateMode(t, msg[1:], false)
+func escapePrivateModeOff(s *Screen, msg string) {
+	escapePrivateMode(s, msg[1:], false)
 }
 
-func escapePrivateModeOn(t *Terminal, msg string) {
-	escapePrivateMode(t, msg[1:], true)
+func escapePrivateModeOn(s *Screen, msg string) {
+	escapePrivateMode(s, msg[1:], true)
 }
 
-func escapeMoveCursor(t *Terminal, msg string) {
+func escapeMoveCursor(s *Screen, msg string) {
 	if !strings.Contains(msg, ";") {
-		t.moveCursor(0, 0)
+		s.moveCursor(0, 0)
 		return
 	}
 
@@ -458,28 +501,32 @@ func escapeMoveCursor(t *Terminal, msg string) {
 		col, _ = strconv.Atoi(parts[1])
 	}
 
-	t.moveCursor(row-1, col-1)
+	s.moveCursor(row-1, col-1)
 }
 
-func escapeRestoreCursor(t *Terminal, s string) {
-	if s != "" {
-		if t.debug {
-			log.Println("Corrupt restore cursor escape", s+"u")
+func escapeRestoreCursor(s *Screen, msg string) {
+	if msg != "" && strings.ContainsRune("<=>?", rune(msg[0])) {
+		s.handleKittyKeyboard(msg)
+		return
+	}
+	if msg != "" {
+		if s.debug {
+			log.Println("Corrupt restore cursor escape", msg+"u")
 		}
 		return
 	}
-	t.moveCursor(t.savedRow, t.savedCol)
+	s.moveCursor(s.savedRow, s.savedCol)
 }
 
-func escapeSaveCursor(t *Terminal, _ string) {
-	t.savedRow = t.cursorRow
-	t.savedCol = t.cursorCol
+func escapeSaveCursor(s *Screen, _ string) {
+	s.savedRow = s.cursorRow
+	s.savedCol = s.cursorCol
 }
 
-func escapeSetScrollArea(t *Terminal, msg string) {
+func escapeSetScrollArea(s *Screen, msg string) {
 	parts := strings.Split(msg, ";")
 	start := 0
-	end := int(t.config.Rows) - 1
+	end := int(s.config.Rows) - 1
 	if len(parts) == 2 {
 		if parts[0] != "" {
 			start, _ = strconv.Atoi(parts[0])
@@ -491,38 +538,38 @@ func escapeSetScrollArea(t *Terminal, msg string) {
 		}
 	}
 
-	t.scrollTop = start
-	t.scrollBottom = end
+	s.scrollTop = start
+	s.scrollBottom = end
 }
 
-func escapeScrollUp(t *Terminal, msg string) {
+func escapeScrollUp(s *Screen, msg string) {
 	lines, _ := strconv.Atoi(msg)
 	if lines == 0 {
 		lines = 1
 	}
 
 	// Ensure we are within the scrollable area
-	if t.cursorRow < t.scrollTop || t.cursorRow > t.scrollBottom {
+	if s.cursorRow < s.scrollTop || s.cursorRow > s.scrollBottom {
 		return
 	}
 
 	// Calculate new cursor position after scrolling
-	newCursorRow := t.cursorRow - lines
+	newCursorRow := s.cursorRow - lines
 
 	// Make sure we don't scroll above the scroll top
-	if newCursorRow < t.scrollTop {
-		newCursorRow = t.scrollTop
+	if newCursorRow < s.scrollTop {
+		newCursorRow = s.scrollTop
 	}
 
 	// Move cursor to the new position
-	t.moveCursor(newCursorRow, t.cursorCol)
+	s.moveCursor(newCursorRow, s.cursorCol)
 
 	// Perform the actual scrolling action
-	for i := t.scrollTop; i <= t.scrollBottom-lines; i++ {
-		t.content.SetRow(i, t.content.Row(i+lines))
+	for i := s.scrollTop; i <= s.scrollBottom-lines; i++ {
+		s.content.copyRow(i, i+lines)
 	}
-	for i := t.scrollBottom - lines + 1; i <= t.scrollBottom; i++ {
-		t.content.SetRow(i, widget.TextGridRow{}) // Clear the last lines
+	for i := s.scrollBottom - lines + 1; i <= s.scrollBottom; i++ {
+		s.content.SetRow(i, widget.TextGridRow{}) // Clear the last lines
 	}
 }
 
@@ -542,37 +589,37 @@ func trimLeftZeros(s string) string {
 	return s[i:]
 }
 
-func escapePrinterMode(t *Terminal, code string) {
+func escapePrinterMode(s *Screen, code string) {
 	switch code {
 	case "5":
-		t.state.printing = true
+		s.state.printing = true
 	case "4":
-		t.state.printing = false
-		if t.printData != nil {
-			if t.printer != nil {
+		s.state.printing = false
+		if s.printData != nil {
+			if s.printer != nil {
 				// spool the printer
-				t.printer.Print(t.printData)
-			} else if t.debug {
+				s.printer.Print(s.printData)
+			} else if s.debug {
 				log.Println("Print data was received but no printer has been set")
 			}
 
 		}
-		t.printData = nil
+		s.printData = nil
 	default:
-		if t.debug {
+		if s.debug {
 			log.Println("Unknown printer mode", code)
 		}
 	}
 }
 
-func escapeDeviceAttribute(t *Terminal, code string) {
+func escapeDeviceAttribute(s *Screen, code string) {
 	if len(code) == 0 { // query
-		_, _ = t.in.Write([]byte{asciiEscape})
-		_, _ = t.in.Write([]byte("[?2;22c")) // printer; color
+		_, _ = s.reply.Write([]byte{asciiEscape})
+		_, _ = s.reply.Write([]byte("[?2;4;22c")) // printer; sixel; color
 		return
 	}
 
-	if t.debug {
+	if s.debug {
 		switch code[0] {
 		case '>':
 			log.Println("Unhandled secondary device attribute", code[1])
@@ -11,15 +11,15 @@ import (
 
 func TestClearScreen(t *testing.T) {
 	term := New()
-	term.config.Columns = 5
-	term.config.Rows = 2
+	term.screen.config.Columns = 5
+	term.screen.config.Rows = 2
 	term.Refresh() // ensure visuals set up
 
-	term.handleOutput([]byte("Hello"))
-	assert.Equal(t, "Hello", term.content.Text())
+	term.screen.handleOutput([]byte("Hello"))
+	assert.Equal(t, "Hello", term.screen.content.Text())
 
-	term.handleEscape("2J")
-	assert.Equal(t, "", term.content.Text())
+	term.screen.handleEscape("2J")
+	assert.Equal(t, "", term.screen.content.Text())
 }
 
 // test clearing the screen by using "scrollback"
@@ -27,31 +27,31 @@ func TestClearScreen(t *testing.T) {
 func TestScrollBack_Tmux(t *testing.T) {
 	// Step 1: Setup a new terminal instance
 	term := New()
-	term.debug = true
-	term.config.Columns = 80 // 80 columns (standard terminal width)
-	term.config.Rows = 5     // Doesn't matter
-	term.Refresh()           // ensure visuals set up
+	term.screen.debug = true
+	term.screen.config.Columns = 80 // 80 columns (standard terminal width)
+	term.screen.config.Rows = 5     // Doesn't matter
+	term.Refresh()                  // ensure visuals set up
 
 	// Step 2: Populate the entire screen with lines using cursor movement
 	for i := 1; i <= 40; i++ {
 		lineText := "Line " + strconv.Itoa(i)
 		// Move the cursor to the beginning of each line using the escape sequence \x1b[{row};{col}H
 		escapeMoveCursor := "\x1b[" + strconv.Itoa(i) + ";1H"
-		term.handleOutput([]byte(escapeMoveCursor + lineText))
+		term.screen.handleOutput([]byte(escapeMoveCursor + lineText))
 	}
 
 	// Step 3: Set up the scroll region and scroll content away
-	term.handleOutput([]byte("\x1b[1;47r")) // Set scroll region from lines 1 to 47
-	term.handleOutput([]byte("\x1b[2;47r")) // Set scroll region again (redundant in most cases)
-	term.handleOutput([]byte("\x1b[46S"))   // Scroll up by 46 lines (this should move almost all content out of view)
+	term.screen.handleOutput([]byte("\x1b[1;47r")) // Set scroll region from lines 1 to 47
+	term.screen.handleOutput([]byte("\x1b[2;47r")) // Set scroll region again (redundant in most cases)
+	term.screen.handleOutput([]byte("\x1b[46S"))   // Scroll up by 46 lines (this should move almost all content out of view)
 
 	// Step 4: Additional escape sequences to clear the screen
-	term.handleOutput([]byte("\x1b[1;1H"))  // Move cursor to the top-left corner
-	term.handleOutput([]byte("\x1b[K"))     // Clear the current line
-	term.handleOutput([]byte("\x1b[1;48r")) // Restore scroll region to the full screen
-	term.handleOutput([]byte("\x1b[1;1H"))  // Move cursor to top-left again
-	term.handleOutput([]byte("\x1b(B"))     // Reset character set
-	term.handleOutput([]byte("\x1b[m"))     // Reset all attributes
+	term.screen.handleOutput([]byte("\x1b[1;1H"))  // Move cursor to the top-left corner
+	term.screen.handleOutput([]byte("\x1b[K"))     // Clear the current line
+	term.screen.handleOutput([]byte("\x1b[1;48r")) // Restore scroll region to the full screen
+	term.screen.handleOutput([]byte("\x1b[1;1H"))  // Move cursor to top-left again
+	term.screen.handleOutput([]byte("\x1b(B"))     // Reset character set
+	term.screen.handleOutput([]byte("\x1b[m"))     // Reset all attributes
 
 	// Step 5: Check the final content of the terminal
 	expectedContent := "" // After scrolling and clearing, the visible area should be empty
@@ -59,10 +59,10 @@ func TestScrollBack_Tmux(t *testing.T) {
 		expectedContent += "\n" // Each row should be an empty line
 	}
 
-	assert.Equal(t, 0, term.cursorRow)
-	assert.Equal(t, 0, term.cursorCol)
+	assert.Equal(t, 0, term.screen.cursorRow)
+	assert.Equal(t, 0, term.screen.cursorCol)
 
-	assert.Equal(t, expectedContent, term.content.Text())
+	assert.Equal(t, expectedContent, term.screen.content.Text())
 }
 
 func TestScrollBack_With_Zero_Back_Buffer(t *testing.T) {
@@ -88,21 +88,21 @@ func TestScrollBack_With_Zero_Back_Buffer(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			// Setup: Create a new terminal instance with a zero back buffer
 			term := New()
-			term.debug = true
-			term.config.Columns = 80 // 80 columns (standard terminal width)
-			term.config.Rows = 5     // Doesn't matter
-			term.Refresh()           // ensure visuals set up
+			term.screen.debug = true
+			term.screen.config.Columns = 80 // 80 columns (standard terminal width)
+			term.screen.config.Rows = 5     // Doesn't matter
+			term.Refresh()                  // ensure visuals set up
 
 			// Step 1: Populate the entire screen with lines using cursor movement
 			for i := 1; i <= tt.linesToAdd; i++ {
 				lineText := "Line " + strconv.Itoa(i)
 				// Move the cursor to the beginning of each line using the escape sequence \x1b[{row};{col}H
 				escapeMoveCursor := "\x1b[" + strconv.Itoa(i) + ";1H" // Move cursor to row i, column 1
-				term.handleOutput([]byte(escapeMoveCursor + lineText))
+				term.screen.handleOutput([]byte(escapeMoveCursor + lineText))
 			}
-			term.handleOutput([]byte("\x1b[1;" + strconv.Itoa(tt.linesToAdd) + "r")) // Set scroll region from lines 1 to linesToAdd
+			term.screen.handleOutput([]byte("\x1b[1;" + strconv.Itoa(tt.linesToAdd) + "r")) // Set scroll region from lines 1 to linesToAdd
 
-			term.handleOutput([]byte("\x1b[" + strconv.Itoa(tt.scrollLines) + "S")) // Scroll up
+			term.screen.handleOutput([]byte("\x1b[" + strconv.Itoa(tt.scrollLines) + "S")) // Scroll up
 
 			// Step 3: Get the current output after scrolling
 			currentOutput := strings.TrimRight(term.Text(), "\n")
@@ -111,98 +111,98 @@ func TestScrollBack_With_Zero_Back_Buffer(t *testing.T) {
 			assert.Equal(t, tt.expectedOutput, currentOutput)
 
 			// Step 5: Check the final content of the terminal
-			assert.Equal(t, tt.expectedCursorRow, term.cursorRow)
-			assert.Equal(t, tt.expectedCursorCol, term.cursorCol)
+			assert.Equal(t, tt.expectedCursorRow, term.screen.cursorRow)
+			assert.Equal(t, tt.expectedCursorCol, term.screen.cursorCol)
 		})
 	}
 }
 
 func TestInsertDeleteChars(t *testing.T) {
 	term := New()
-	term.config.Columns = 5
-	term.config.Rows = 2
+	term.screen.config.Columns = 5
+	term.screen.config.Rows = 2
 	term.Refresh() // ensure visuals set up
 
-	term.handleOutput([]byte("Hello"))
-	assert.Equal(t, "Hello", term.content.Text())
+	term.screen.handleOutput([]byte("Hello"))
+	assert.Equal(t, "Hello", term.screen.content.Text())
 
-	term.moveCursor(0, 2)
-	term.handleEscape("2@")
-	assert.Equal(t, "He  llo", term.content.Text())
-	term.handleEscape("3P")
-	assert.Equal(t, "Helo", term.content.Text())
+	term.screen.moveCursor(0, 2)
+	term.screen.handleEscape("2@")
+	assert.Equal(t, "He  llo", term.screen.content.Text())
+	term.screen.handleEscape("3P")
+	assert.Equal(t, "Helo", term.screen.content.Text())
 }
 
 func TestEraseLine(t *testing.T) {
 	term := New()
-	term.config.Columns = 5
-	term.config.Rows = 2
+	term.screen.config.Columns = 5
+	term.screen.config.Rows = 2
 	term.Refresh() // ensure visuals set up
 
-	term.handleOutput([]byte("Hello"))
-	assert.Equal(t, "Hello", term.content.Text())
+	term.screen.handleOutput([]byte("Hello"))
+	assert.Equal(t, "Hello", term.screen.content.Text())
 
-	term.moveCursor(0, 2)
-	term.handleEscape("K")
-	assert.Equal(t, "He", term.content.Text())
+	term.screen.moveCursor(0, 2)
+	term.screen.handleEscape("K")
+	assert.Equal(t, "He", term.screen.content.Text())
 }
 
 func TestCursorMove(t *testing.T) {
 	term := New()
-	term.config.Columns = 5
-	term.config.Rows = 2
+	term.screen.config.Columns = 5
+	term.screen.config.Rows = 2
 	term.Refresh() // ensure visuals set up
 
-	term.handleOutput([]byte("Hello"))
-	assert.Equal(t, 0, term.cursorRow)
-	assert.Equal(t, 5, term.cursorCol)
+	term.screen.handleOutput([]byte("Hello"))
+	assert.Equal(t, 0, term.screen.cursorRow)
+	assert.Equal(t, 5, term.screen.cursorCol)
 
-	term.handleEscape("1;4H")
-	assert.Equal(t, 0, term.cursorRow)
-	assert.Equal(t, 3, term.cursorCol)
+	term.screen.handleEscape("1;4H")
+	assert.Equal(t, 0, term.screen.cursorRow)
+	assert.Equal(t, 3, term.screen.cursorCol)
 
-	term.handleEscape("2D")
-	assert.Equal(t, 0, term.cursorRow)
-	assert.Equal(t, 1, term.cursorCol)
+	term.screen.handleEscape("2D")
+	assert.Equal(t, 0, term.screen.cursorRow)
+	assert.Equal(t, 1, term.screen.cursorCol)
 
-	term.handleEscape("2C")
-	assert.Equal(t, 0, term.cursorRow)
-	assert.Equal(t, 3, term.cursorCol)
+	term.screen.handleEscape("2C")
+	assert.Equal(t, 0, term.screen.cursorRow)
+	assert.Equal(t, 3, term.screen.cursorCol)
 
-	term.handleEscape("1B")
-	assert.Equal(t, 1, term.cursorRow)
-	assert.Equal(t, 3, term.cursorCol)
+	term.screen.handleEscape("1B")
+	assert.Equal(t, 1, term.screen.cursorRow)
+	assert.Equal(t, 3, term.screen.cursorCol)
 
-	term.handleEscape("1A")
-	assert.Equal(t, 0, term.cursorRow)
-	assert.Equal(t, 3, term.cursorCol)
+	term.screen.handleEscape("1A")
+	assert.Equal(t, 0, term.screen.cursorRow)
+	assert.Equal(t, 3, term.screen.cursorCol)
 }
 
 func TestCursorMove_Overflow(t *testing.T) {
 	term := New()
-	term.config.Columns = 2
-	term.config.Rows = 2
+	term.screen.config.Columns = 2
+	term.screen.config.Rows = 2
 	term.Refresh() // ensure visuals set up
 
-	term.handleEscape("2;2H")
-	assert.Equal(t, 1, term.cursorRow)
-	assert.Equal(t, 1, term.cursorCol)
+	term.screen.handleEscape("2;2H")
+	assert.Equal(t, 1, term.screen.cursorRow)
+	assert.Equal(t, 1, term.screen.cursorCol)
 
-	term.handleEscape("2D")
-	assert.Equal(t, 1, term.cursorRow)
-	assert.Equal(t, 0, term.cursorCol)
+	term.screen.handleEscape("2D")
+	assert.Equal(t, 1, term.screen.cursorRow)
+	assert.Equal(t, 0, term.screen.cursorCol)
 
-	term.handleEscape("5C")
-	assert.Equal(t, 1, term.cursorRow)
-	assert.Equal(t, 1, term.cursorCol)
+	term.screen.handleEscape("5C")
+	assert.Equal(t, 1, term.screen.cursorRow)
+	assert.Equal(t, 1, term.screen.cursorCol)
 
-	term.handleEscape("5A")
-	assert.Equal(t, 0, term.cursorRow)
-	assert.Equal(t, 1, term.cursorCol)
+	term.screen.handleEscape("5A")
+	assert.Equal(t, 0, term.screen.cursorRow)
+	assert.Equal(t, 1, term.screen.cursorCol)
 
-	term.handleEscape("4B")
-	assert.Equal(t, 1, term.cursorRow)
-	assert.Equal(t, 1, term.cursorCol)
+	term.screen.handleEscape("4B")
+	assert.Equal(t, 1, term.screen.cursorRow)
+	assert.Equal(t, 1, term.screen.cursorCol)
 }
 
 func TestTrimLeftZeros(t *testing.T) {
@@ -289,13 +289,13 @@ func TestHandleOutput_NewLineMode(t *testing.T) {
 			term := New()
 			term.Resize(fyne.NewSize(500, 500))
 
-			term.handleOutput([]byte(tt.input))
+			term.screen.handleOutput([]byte(tt.input))
 
-			assert.Equal(t, tt.expectedCursorRow, term.cursorRow)
-			assert.Equal(t, tt.expectedCursorCol, term.cursorCol)
-			assert.Equal(t, tt.expectedNewLineMode, term.newLineMode)
-			assert.Equal(t, tt.expectedContentText, term.content.Text())
-			assert.Equal(t, tt.expectedContentRowCount, len(term.content.Rows))
+			assert.Equal(t, tt.expectedCursorRow, term.screen.cursorRow)
+			assert.Equal(t, tt.expectedCursorCol, term.screen.cursorCol)
+			assert.Equal(t, tt.expectedNewLineMode, term.screen.newLineMode)
+			assert.Equal(t, tt.expectedContentText, term.screen.content.Text())
+			assert.Equal(t, tt.expectedContentRowCount, len(term.screen.content.Rows))
 		})
 	}
 }
@@ -336,12 +336,12 @@ func TestTerminalEscapeSequences(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.description, func(t *testing.T) {
 			term := New()
-			term.config.Columns = 10
-			term.config.Rows = 1
+			term.screen.config.Columns = 10
+			term.screen.config.Rows = 1
 			term.Refresh() // ensure visuals set up
 
-			term.handleOutput([]byte(testCase.input))
-			actual := term.content.Text()
+			term.screen.handleOutput([]byte(testCase.input))
+			actual := term.screen.content.Text()
 			if actual != testCase.expected {
 				t.Errorf("Expected: %s, Got: %s", testCase.expected, actual)
 			}
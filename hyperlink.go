@@ -0,0 +1,80 @@
+package terminal
+
+import (
+	"net/url"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+)
+
+// MouseIn is called when the mouse enters the terminal, so we can show if it is over a hyperlink.
+func (t *Terminal) MouseIn(ev *desktop.MouseEvent) {
+	t.MouseMoved(ev)
+}
+
+// MouseMoved is called when the mouse moves over the terminal, hyperlinks are underlined while hovered.
+// The motion is also reported to the application if it asked for it.
+func (t *Terminal) MouseMoved(ev *desktop.MouseEvent) {
+	t.setHoveredLink(t.linkAt(ev.Position))
+	t.mouseMoved(ev.Modifier, ev.Position)
+}
+
+// MouseOut is called when the mouse leaves the terminal.
+func (t *Terminal) MouseOut() {
+	t.setHoveredLink(nil)
+}
+
+// linkAt returns the hyperlink attached to the text at a position in the terminal, or nil if there is none.
+func (t *Terminal) linkAt(pos fyne.Position) *widget2.Hyperlink {
+	if t.view == nil {
+		return nil
+	}
+
+	p := t.getTermPosition(pos)
+	if p.Row < 1 || p.Row > len(t.view.Rows) {
+		return nil
+	}
+	cells := t.view.Rows[p.Row-1].Cells
+	if p.Col < 1 || p.Col > len(cells) {
+		return nil
+	}
+	return widget2.CellHyperlink(cells[p.Col-1])
+}
+
+// openLink passes a hyperlink that was clicked to the OnHyperlink hook, or opens it if the hook did not handle it.
+// Only web and mailto links are opened, as the user cannot see where a link goes before clicking it.
+func (t *Terminal) openLink(link *widget2.Hyperlink) {
+	if t.OnHyperlink != nil && t.OnHyperlink(link.ID, link.URI) {
+		return
+	}
+
+	u, err := url.Parse(link.URI)
+	if err != nil {
+		fyne.LogError("Failed to parse hyperlink "+link.URI, err)
+		return
+	}
+	if !openableLink(u) {
+		fyne.LogError("Not opening hyperlink "+link.URI+", only http, https and mailto links are opened", nil)
+		return
+	}
+	if err = fyne.CurrentApp().OpenURL(u); err != nil {
+		fyne.LogError("Failed to open hyperlink "+link.URI, err)
+	}
+}
+
+// openableLink returns true if a link uses a scheme that is safe to open without asking, http, https or mailto.
+func openableLink(u *url.URL) bool {
+	switch u.Scheme {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+func (t *Terminal) setHoveredLink(link *widget2.Hyperlink) {
+	t.hoveredLink = link
+	if t.view != nil {
+		t.view.SetHoveredLink(link)
+	}
+}
@@ -0,0 +1,59 @@
+package terminal
+
+import (
+	"net/url"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerminal_Hyperlink(t *testing.T) {
+	test.NewApp()
+	term := New()
+	w := test.NewTempWindow(t, term)
+	w.Resize(fyne.NewSize(200, 100))
+	_, _ = term.screen.Write([]byte("ab\x1b]8;;https://fyne.io\x07link\x1b]8;;\x07"))
+	term.Refresh()
+
+	cell := term.guessCellSize()
+	onLink := fyne.NewPos(cell.Width*3.5, cell.Height/2)
+	term.MouseMoved(&desktop.MouseEvent{PointEvent: fyne.PointEvent{Position: onLink}})
+	assert.Equal(t, desktop.PointerCursor, term.Cursor())
+	term.MouseMoved(&desktop.MouseEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(cell.Width/2, cell.Height/2)}})
+	assert.Equal(t, desktop.DefaultCursor, term.Cursor())
+
+	var opened string
+	term.OnHyperlink = func(_, uri string) bool {
+		opened = uri
+		return true
+	}
+	click := &desktop.MouseEvent{PointEvent: fyne.PointEvent{Position: onLink}, Button: desktop.MouseButtonPrimary}
+	term.MouseDown(click)
+	assert.Equal(t, "", opened)
+
+	click.Modifier = fyne.KeyModifierControl
+	term.MouseDown(click)
+	assert.Equal(t, "https://fyne.io", opened)
+
+	term.MouseOut()
+	assert.Equal(t, desktop.DefaultCursor, term.Cursor())
+}
+
+func TestOpenableLink(t *testing.T) {
+	for uri, want := range map[string]bool{
+		"https://fyne.io":       true,
+		"HTTP://fyne.io":        true,
+		"mailto:info@fyne.io":   true,
+		"file:///etc/passwd":    false,
+		"ssh://example.com":     false,
+		"x-custom-scheme:thing": false,
+		"/relative/path":        false,
+	} {
+		u, err := url.Parse(uri)
+		assert.Nil(t, err)
+		assert.Equal(t, want, openableLink(u), uri)
+	}
+}
@@ -0,0 +1,78 @@
+package terminal
+
+import (
+	"image"
+
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+)
+
+const (
+	defaultCellWidth  = 10
+	defaultCellHeight = 20
+)
+
+// SetCellSize sets the size in pixels of a character cell, which decides how many cells an image covers.
+// A Terminal sets this when it is resized, the default for a Screen is 10 by 20 pixels.
+func (s *Screen) SetCellSize(width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	s.cellSize = image.Pt(width, height)
+}
+
+// placeImage shows an image in the cells from the cursor, keeping their text. The cells are scaled to fit
+// the given columns and rows, or the image is shown at its own size if these are 0.
+// Parts of the image beyond the right edge are lost.
+// The cursor is left on the last row of the image, in the column where the image starts.
+// The number of columns and rows that the image covers is returned.
+func (s *Screen) placeImage(img *widget2.CellImage, cols, rows int) (int, int) {
+	size := img.Image.Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return 0, 0
+	}
+	if cols <= 0 {
+		cols = (size.X + s.cellSize.X - 1) / s.cellSize.X
+	}
+	if rows <= 0 {
+		rows = (size.Y + s.cellSize.Y - 1) / s.cellSize.Y
+	}
+
+	// cells of the image are the same size as the cells of the screen, unless the image is scaled to fit
+	img.CellWidth, img.CellHeight = s.cellSize.X, s.cellSize.Y
+	if cols*s.cellSize.X < size.X || rows*s.cellSize.Y < size.Y ||
+		(cols-1)*s.cellSize.X >= size.X || (rows-1)*s.cellSize.Y >= size.Y {
+		img.CellWidth = (size.X + cols - 1) / cols
+		img.CellHeight = (size.Y + rows - 1) / rows
+	}
+
+	startCol := s.cursorCol
+	for row := 0; row < rows; row++ {
+		if row > 0 {
+			s.lineDown()
+		}
+
+		for col := 0; col < cols && startCol+col < int(s.config.Columns); col++ {
+			cell := s.Cell(s.cursorRow, startCol+col)
+			widget2.SetCellImage(&cell, img, col, row, highlightBitMask)
+			s.content.SetCell(s.cursorRow, startCol+col, cell)
+		}
+		cells := s.content.Row(s.cursorRow).Cells
+		for i := 0; i < startCol && i < len(cells); i++ {
+			if cells[i].Rune == 0 {
+				cells[i].Rune = ' '
+			}
+		}
+	}
+	s.moveCursor(s.cursorRow, startCol)
+	return cols, rows
+}
+
+// lineDown moves the cursor down a row, scrolling if it is at the bottom of the scroll region.
+func (s *Screen) lineDown() {
+	if s.cursorRow == s.scrollBottom {
+		s.scrollDown()
+		return
+	}
+	s.moveCursor(s.cursorRow+1, s.cursorCol)
+}
@@ -2,6 +2,7 @@ package terminal
 
 import (
 	"runtime"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -14,6 +15,14 @@ var lastKeyTime = time.Now()
 // TypedRune is called when the user types a visible character
 func (t *Terminal) TypedRune(r rune) {
 	lastKeyTime = time.Now()
+	t.scrollToBottom()
+	if t.keySent { // the character typed was replaced by the key sent for it
+		t.keySent = false
+		return
+	}
+	if t.typedKittyRune(r) {
+		return
+	}
 	b := make([]byte, utf8.UTFMax)
 	size := utf8.EncodeRune(b, r)
 	_, _ = t.in.Write(b[:size])
@@ -22,8 +31,12 @@ func (t *Terminal) TypedRune(r rune) {
 // TypedKey will be called if a non-printable keyboard event occurs
 func (t *Terminal) TypedKey(e *fyne.KeyEvent) {
 	lastKeyTime = time.Now()
-	if t.keyboardState.shiftPressed {
-		t.keyTypedWithShift(e)
+	if t.keyboardState.shiftPressed && t.typedHistoryKey(e) {
+		return
+	}
+	t.scrollToBottom()
+	mods := t.heldModifiers()
+	if t.typedKittyKey(e.Name, mods) || t.typedKeypadKey(e, mods) || t.typedModifiedKey(e.Name, mods) || t.typedModifyOtherKey(e.Name, mods) {
 		return
 	}
 
@@ -31,7 +44,7 @@ func (t *Terminal) TypedKey(e *fyne.KeyEvent) {
 	case fyne.KeyReturn:
 		_, _ = t.in.Write([]byte{'\r'})
 	case fyne.KeyEnter:
-		if t.newLineMode {
+		if t.screen.newLineMode {
 			_, _ = t.in.Write([]byte{'\r'})
 			return
 		}
@@ -83,53 +96,78 @@ func (t *Terminal) TypedKey(e *fyne.KeyEvent) {
 	}
 }
 
-func (t *Terminal) keyTypedWithShift(e *fyne.KeyEvent) {
+// typedHistoryKey handles Shift+PageUp/PageDown paging through the history.
+// It returns false if there is no history to move through, so the key can be sent to the application.
+func (t *Terminal) typedHistoryKey(e *fyne.KeyEvent) bool {
+	if t.screen.altBufferActive {
+		return false
+	}
+
+	page := int(t.screen.config.Rows) - 1
+	if page < 1 {
+		page = 1
+	}
 	switch e.Name {
-	case fyne.KeyF1:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '2', '5', '~'})
-	case fyne.KeyF2:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '2', '6', '~'})
-	case fyne.KeyF3:
-		_, _ = t.in.Write([]byte{asciiEscape, 'O', 'R', ';', '2', '~'})
-	case fyne.KeyF4:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '1', ';', '2', 'S'})
-	case fyne.KeyF5:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '1', '5', ';', '2', '~'})
-	case fyne.KeyF6:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '1', '7', ';', '2', '~'})
-	case fyne.KeyF7:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '1', '8', ';', '2', '~'})
-	case fyne.KeyF8:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '1', '9', ';', '2', '~'})
-	case fyne.KeyF9:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '2', '0', ';', '2', '~'})
-	case fyne.KeyF10:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '2', '1', ';', '2', '~'})
-	case fyne.KeyF11:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '2', '3', ';', '2', '~'})
-	case fyne.KeyF12:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '2', '4', ';', '2', '~'})
 	case fyne.KeyPageUp:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '5', ';', '2', '~'})
+		if t.screen.scrollback.len() == 0 {
+			return false
+		}
+		t.scrollHistory(page)
+		return true
 	case fyne.KeyPageDown:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '6', ';', '2', '~'})
-	case fyne.KeyHome:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '1', ';', '2', 'H'})
-	case fyne.KeyInsert:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '2', ';', '2', '~'})
-	case fyne.KeyDelete:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '3', ';', '2', '~'})
-	case fyne.KeyEnd:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', '1', ';', '2', 'F'})
-	case fyne.KeyUp:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', 'A', ';', '2'})
-	case fyne.KeyDown:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', 'B', ';', '2'})
-	case fyne.KeyLeft:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', 'D', ';', '2'})
-	case fyne.KeyRight:
-		_, _ = t.in.Write([]byte{asciiEscape, '[', 'C', ';', '2'})
+		if t.scrollOffset == 0 {
+			return false
+		}
+		t.scrollHistory(-page)
+		return true
 	}
+	return false
+}
+
+// xtermModifiedKeys are the special keys that are sent with modifiers as CSI 1;modifiers final,
+// or CSI code;modifiers ~ for the keys that end in a tilde.
+var xtermModifiedKeys = map[fyne.KeyName]csiKey{
+	fyne.KeyUp:       {1, 'A'},
+	fyne.KeyDown:     {1, 'B'},
+	fyne.KeyRight:    {1, 'C'},
+	fyne.KeyLeft:     {1, 'D'},
+	fyne.KeyHome:     {1, 'H'},
+	fyne.KeyEnd:      {1, 'F'},
+	fyne.KeyF1:       {1, 'P'},
+	fyne.KeyF2:       {1, 'Q'},
+	fyne.KeyF3:       {1, 'R'},
+	fyne.KeyF4:       {1, 'S'},
+	fyne.KeyInsert:   {2, '~'},
+	fyne.KeyDelete:   {3, '~'},
+	fyne.KeyPageUp:   {5, '~'},
+	fyne.KeyPageDown: {6, '~'},
+	fyne.KeyF5:       {15, '~'},
+	fyne.KeyF6:       {17, '~'},
+	fyne.KeyF7:       {18, '~'},
+	fyne.KeyF8:       {19, '~'},
+	fyne.KeyF9:       {20, '~'},
+	fyne.KeyF10:      {21, '~'},
+	fyne.KeyF11:      {23, '~'},
+	fyne.KeyF12:      {24, '~'},
+}
+
+// typedModifiedKey sends a special key typed with Shift, Alt, Ctrl or Meta in the xterm encoding.
+// It returns false if no modifiers are held or the key has no modified form.
+func (t *Terminal) typedModifiedKey(name fyne.KeyName, mods fyne.KeyModifier) bool {
+	if mods == 0 {
+		return false
+	}
+	if name == fyne.KeyTab && mods == fyne.KeyModifierShift {
+		_, _ = t.Write([]byte{asciiEscape, '[', 'Z'}) // back tab
+		return true
+	}
+
+	key, ok := xtermModifiedKeys[name]
+	if !ok {
+		return false
+	}
+	_, _ = t.Write(csiKeySequence(key, 0, mods, kittyKeyPress, 0))
+	return true
 }
 
 func (t *Terminal) trackKeyboardState(down bool, e *fyne.KeyEvent) {
@@ -137,60 +175,102 @@ func (t *Terminal) trackKeyboardState(down bool, e *fyne.KeyEvent) {
 	case desktop.KeyShiftLeft:
 		t.keyboardState.shiftPressed = down
 	case desktop.KeyAltLeft:
-		t.keyboardState.altPressed = down
+		t.keyboardState.altLeftPressed = down
 	case desktop.KeyControlLeft:
 		t.keyboardState.ctrlPressed = down
 	case desktop.KeyShiftRight:
 		t.keyboardState.shiftPressed = down
 	case desktop.KeyAltRight:
-		t.keyboardState.altPressed = down
+		t.keyboardState.altRightPressed = down
 	case desktop.KeyControlRight:
 		t.keyboardState.ctrlPressed = down
+	case desktop.KeySuperLeft, desktop.KeySuperRight:
+		t.keyboardState.superPressed = down
+	}
+}
+
+// heldModifiers returns the modifier keys that are currently held down.
+func (t *Terminal) heldModifiers() fyne.KeyModifier {
+	var mods fyne.KeyModifier
+	if t.keyboardState.shiftPressed {
+		mods |= fyne.KeyModifierShift
+	}
+	if t.keyboardState.ctrlPressed {
+		mods |= fyne.KeyModifierControl
+	}
+	if t.keyboardState.altLeftPressed || t.keyboardState.altRightPressed {
+		mods |= fyne.KeyModifierAlt
+	}
+	if t.keyboardState.superPressed {
+		mods |= fyne.KeyModifierSuper
 	}
+	return mods
 }
 
 // KeyDown is called when we get a down key event
 func (t *Terminal) KeyDown(e *fyne.KeyEvent) {
 	t.trackKeyboardState(true, e)
+	t.keyHeld, t.keyRepeating = e.Name, false
+	t.keySent = false
+	t.pressedKittyModifier(e.Name)
 }
 
 // KeyUp is called when we get an up key event
 func (t *Terminal) KeyUp(e *fyne.KeyEvent) {
 	t.trackKeyboardState(false, e)
+	if e.Name == t.keyHeld {
+		t.keyHeld = ""
+	}
+	t.releasedKittyKey(e.Name)
 }
 
 // FocusGained notifies the terminal that it has focus
 func (t *Terminal) FocusGained() {
 	t.focused = true
 	t.Refresh()
+	if t.screen.focusReporting {
+		_, _ = t.Write([]byte{asciiEscape, '[', 'I'})
+	}
 }
 
 // TypedShortcut handles key combinations, we pass them on to the tty.
 func (t *Terminal) TypedShortcut(s fyne.Shortcut) {
+	t.shortcutHandled = false
+	t.ShortcutHandler.TypedShortcut(s)
+	if t.shortcutHandled {
+		return
+	}
+
+	if ks, ok := s.(fyne.KeyboardShortcut); ok && ks.Key() != fyne.KeyInsert && t.typedKittyShortcut(ks.Key(), ks.Mod()) {
+		t.scrollToBottom()
+		return
+	}
+	if name, mods, ok := shortcutKey(s); ok && (t.typedModifiedKey(name, mods) || t.typedModifyOtherKey(name, mods)) {
+		t.scrollToBottom()
+		return
+	}
 	if ds, ok := s.(*desktop.CustomShortcut); ok {
-		t.ShortcutHandler.TypedShortcut(s) // it's not clear how we can check if this consumed the event
+		mods := ds.Modifier
+		meta := mods&fyne.KeyModifierAlt != 0 && t.altIsMeta()
+		if meta {
+			mods &^= fyne.KeyModifierAlt
+		}
+		b := legacyKeyBytes(ds.KeyName, mods)
+		if b == nil || (!meta && mods != fyne.KeyModifierControl) {
+			return
+		}
 
-		// handle CTRL+A to CTRL+_ and everything in-between
-		if ds.Modifier == fyne.KeyModifierControl && len(ds.KeyName) > 0 {
-			char := ds.KeyName[0]
-			off := char - 'A' + 1
-			switch {
-			case ds.Key() == fyne.KeySpace:
-				fallthrough
-			case ds.Key() == "@":
-				off = 0
-				fallthrough
-			case char >= 'A' && char <= '_':
-				_, _ = t.in.Write([]byte{off})
-			}
+		t.scrollToBottom()
+		if meta {
+			t.typeMeta(b)
+			t.keySent = runtime.GOOS == "darwin" // macOS will also send the character typed with Option
+		} else {
+			_, _ = t.in.Write(b)
 		}
 		return
 	}
 
-	if runtime.GOOS == "darwin" {
-		// do the default thing for macOS as they separate ctrl/cmd
-		t.ShortcutHandler.TypedShortcut(s)
-	} else {
+	if runtime.GOOS != "darwin" {
 		// we need to override the default ctrl-X/C/V/A for non-mac and do it ourselves
 		switch sh := s.(type) {
 		case *fyne.ShortcutCut:
@@ -221,10 +301,119 @@ func (t *Terminal) TypedShortcut(s fyne.Shortcut) {
 	}
 }
 
+// legacyKeyBytes returns what is sent for a key typed with Ctrl, with Shift or on its own,
+// or nil if it is not a key that types text or a control character.
+func legacyKeyBytes(name fyne.KeyName, mods fyne.KeyModifier) []byte {
+	switch mods {
+	case fyne.KeyModifierControl:
+		// handle CTRL+A to CTRL+_ and everything in-between
+		if name == fyne.KeySpace || name == "@" {
+			return []byte{0}
+		}
+		if len(name) == 1 && name[0] >= 'A' && name[0] <= '_' {
+			return []byte{name[0] - 'A' + 1}
+		}
+	case 0, fyne.KeyModifierShift:
+		switch name {
+		case fyne.KeySpace:
+			return []byte{' '}
+		case fyne.KeyReturn, fyne.KeyEnter:
+			return []byte{'\r'}
+		case fyne.KeyTab:
+			return []byte{'\t'}
+		case fyne.KeyBackspace:
+			return []byte{asciiBackspace}
+		case fyne.KeyEscape:
+			return []byte{asciiEscape}
+		}
+		if len(name) == 1 {
+			if mods == 0 {
+				return []byte(strings.ToLower(string(name)))
+			}
+			return []byte(name)
+		}
+	}
+	return nil
+}
+
+// altIsMeta returns true if the Alt keys held should change the key typed, rather than type other characters.
+// On macOS the Option keys type characters unless they were set to act as Alt.
+func (t *Terminal) altIsMeta() bool {
+	if runtime.GOOS != "darwin" {
+		return true
+	}
+
+	return (t.keyboardState.altLeftPressed && t.optionAsAltLeft) ||
+		(t.keyboardState.altRightPressed && t.optionAsAltRight)
+}
+
+// typeMeta sends a key typed with Alt, prefixed by ESC or with its eighth bit set as the application asked for.
+func (t *Terminal) typeMeta(b []byte) {
+	switch {
+	case !t.screen.disableAltEscape:
+		_, _ = t.in.Write(append([]byte{asciiEscape}, b...))
+	case t.screen.eightBitMeta && len(b) == 1 && b[0] < 0x80:
+		_, _ = t.in.Write(utf8.AppendRune(nil, rune(b[0]|0x80)))
+	default:
+		_, _ = t.in.Write(b)
+	}
+}
+
+// shortcutKey returns the key and modifiers that were typed for a shortcut that can be sent to the application.
+// Fyne delivers Ctrl+A, C, V, X, Y and Z as its standard shortcuts, so these are mapped back to their keys.
+// The clipboard shortcuts with Insert and Delete are handled by the terminal, as are the standard shortcuts
+// of macOS, which use Command.
+func shortcutKey(s fyne.Shortcut) (fyne.KeyName, fyne.KeyModifier, bool) {
+	switch sh := s.(type) {
+	case *desktop.CustomShortcut:
+		return sh.KeyName, sh.Modifier, true
+	case *fyne.ShortcutCut:
+		if sh.Secondary {
+			return "", 0, false
+		}
+	case *fyne.ShortcutCopy:
+		if sh.Secondary {
+			return "", 0, false
+		}
+	case *fyne.ShortcutPaste:
+		if sh.Secondary {
+			return "", 0, false
+		}
+	case *fyne.ShortcutUndo, *fyne.ShortcutRedo, *fyne.ShortcutSelectAll:
+	default:
+		return "", 0, false
+	}
+	if runtime.GOOS == "darwin" {
+		return "", 0, false
+	}
+
+	ks := s.(fyne.KeyboardShortcut)
+	return ks.Key(), ks.Mod(), true
+}
+
+// SetAltSendsEscape sets if keys typed with Alt are sent prefixed by ESC, which is the default and what shells
+// expect for shortcuts such as Alt+B and Alt+F. Applications can also change this with the ?1036 and ?1039 modes.
+func (t *Terminal) SetAltSendsEscape(escape bool) {
+	t.screen.disableAltEscape = !escape
+}
+
+// SetOptionAsAlt sets which of the Option keys on macOS act as Alt, rather than typing special characters.
+// Neither does by default. Alt keys on other systems always act as Alt.
+func (t *Terminal) SetOptionAsAlt(left, right bool) {
+	t.optionAsAltLeft, t.optionAsAltRight = left, right
+}
+
 // FocusLost tells the terminal it no longer has focus
 func (t *Terminal) FocusLost() {
 	t.focused = false
+	// modifiers released while another window has focus are not seen, so don't keep them
+	t.keyboardState.shiftPressed, t.keyboardState.ctrlPressed = false, false
+	t.keyboardState.altLeftPressed, t.keyboardState.altRightPressed = false, false
+	t.keyboardState.superPressed = false
 	t.Refresh()
+	if t.screen.focusReporting {
+		_, _ = t.Write([]byte{asciiEscape, '[', 'O'})
+	}
 }
 
 // Focused is used to determine if this terminal currently has focus
@@ -234,7 +423,7 @@ func (t *Terminal) Focused() bool {
 
 func (t *Terminal) typeCursorKey(key fyne.KeyName) {
 	cursorPrefix := byte('[')
-	if t.bufferMode {
+	if t.screen.bufferMode {
 		cursorPrefix = 'O'
 	}
 
@@ -3,10 +3,12 @@ package terminal
 import (
 	"bytes"
 	"io"
+	"runtime"
 	"testing"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/driver/desktop"
+	"github.com/stretchr/testify/assert"
 )
 
 // NopCloser returns a WriteCloser with a no-op Close method wrapping
@@ -42,9 +44,9 @@ func TestTerminal_TypedKey(t *testing.T) {
 		"F10":            {fyne.KeyF10, false, false, []byte{asciiEscape, '[', '2', '1', '~'}},
 		"F11":            {fyne.KeyF11, false, false, []byte{asciiEscape, '[', '2', '3', '~'}},
 		"F12":            {fyne.KeyF12, false, false, []byte{asciiEscape, '[', '2', '4', '~'}},
-		"Shift+F1":       {fyne.KeyF1, false, true, []byte{asciiEscape, '[', '2', '5', '~'}},
-		"Shift+F2":       {fyne.KeyF2, false, true, []byte{asciiEscape, '[', '2', '6', '~'}},
-		"Shift+F3":       {fyne.KeyF3, false, true, []byte{asciiEscape, 'O', 'R', ';', '2', '~'}},
+		"Shift+F1":       {fyne.KeyF1, false, true, []byte{asciiEscape, '[', '1', ';', '2', 'P'}},
+		"Shift+F2":       {fyne.KeyF2, false, true, []byte{asciiEscape, '[', '1', ';', '2', 'Q'}},
+		"Shift+F3":       {fyne.KeyF3, false, true, []byte{asciiEscape, '[', '1', ';', '2', 'R'}},
 		"Shift+F4":       {fyne.KeyF4, false, true, []byte{asciiEscape, '[', '1', ';', '2', 'S'}},
 		"Shift+F5":       {fyne.KeyF5, false, true, []byte{asciiEscape, '[', '1', '5', ';', '2', '~'}},
 		"Shift+F6":       {fyne.KeyF6, false, true, []byte{asciiEscape, '[', '1', '7', ';', '2', '~'}},
@@ -60,10 +62,12 @@ func TestTerminal_TypedKey(t *testing.T) {
 		"Shift+Insert":   {fyne.KeyInsert, false, true, []byte{asciiEscape, '[', '2', ';', '2', '~'}},
 		"Shift+Delete":   {fyne.KeyDelete, false, true, []byte{asciiEscape, '[', '3', ';', '2', '~'}},
 		"Shift+End":      {fyne.KeyEnd, false, true, []byte{asciiEscape, '[', '1', ';', '2', 'F'}},
-		"Shift+Up":       {fyne.KeyUp, false, true, []byte{asciiEscape, '[', 'A', ';', '2'}},
-		"Shift+Down":     {fyne.KeyDown, false, true, []byte{asciiEscape, '[', 'B', ';', '2'}},
-		"Shift+Left":     {fyne.KeyLeft, false, true, []byte{asciiEscape, '[', 'D', ';', '2'}},
-		"Shift+Right":    {fyne.KeyRight, false, true, []byte{asciiEscape, '[', 'C', ';', '2'}},
+		"Shift+Up":       {fyne.KeyUp, false, true, []byte{asciiEscape, '[', '1', ';', '2', 'A'}},
+		"Shift+Down":     {fyne.KeyDown, false, true, []byte{asciiEscape, '[', '1', ';', '2', 'B'}},
+		"Shift+Left":     {fyne.KeyLeft, false, true, []byte{asciiEscape, '[', '1', ';', '2', 'D'}},
+		"Shift+Right":    {fyne.KeyRight, false, true, []byte{asciiEscape, '[', '1', ';', '2', 'C'}},
+		"Shift+Tab":      {fyne.KeyTab, false, true, []byte{asciiEscape, '[', 'Z'}},
+		"Shift+Return":   {fyne.KeyReturn, false, true, []byte{'\r'}},
 
 		"PageUp":    {fyne.KeyPageUp, false, false, []byte{asciiEscape, '[', '5', '~'}},
 		"PageDown":  {fyne.KeyPageDown, false, false, []byte{asciiEscape, '[', '6', '~'}},
@@ -85,7 +89,7 @@ func TestTerminal_TypedKey(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			// Creating a mock terminal
 			inBuffer := bytes.NewBuffer([]byte{})
-			term := &Terminal{in: NopCloser(inBuffer), bufferMode: tt.bufferMode}
+			term := &Terminal{in: NopCloser(inBuffer), screen: &Screen{bufferMode: tt.bufferMode}}
 			term.keyboardState.shiftPressed = tt.shiftPressed
 			keyEvent := &fyne.KeyEvent{Name: tt.key}
 
@@ -116,7 +120,7 @@ func TestTerminal_TypedKey_LineMode(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			// Creating a mock terminal
 			inBuffer := bytes.NewBuffer([]byte{})
-			term := &Terminal{in: NopCloser(inBuffer), newLineMode: tt.newLineMode}
+			term := &Terminal{in: NopCloser(inBuffer), screen: &Screen{newLineMode: tt.newLineMode}}
 			keyEvent := &fyne.KeyEvent{Name: tt.key}
 
 			term.TypedKey(keyEvent)
@@ -130,14 +134,38 @@ func TestTerminal_TypedKey_LineMode(t *testing.T) {
 }
 
 func TestTerminal_TypedShortcut(t *testing.T) {
+	altWant := func(b ...byte) []byte {
+		if runtime.GOOS == "darwin" {
+			return []byte{}
+		}
+		return b
+	}
 	tests := map[string]struct {
 		shortcut fyne.Shortcut
 		want     []byte
 	}{
-		"LeftOption+U": {
+		"LeftOption+U": { // Option types characters on macOS, elsewhere Alt sends ESC
 			shortcut: &desktop.CustomShortcut{
 				Modifier: fyne.KeyModifierAlt,
 				KeyName:  fyne.KeyU},
+			want: altWant(asciiEscape, 'u'),
+		},
+		"Alt+Shift+B": {
+			shortcut: &desktop.CustomShortcut{
+				Modifier: fyne.KeyModifierAlt | fyne.KeyModifierShift,
+				KeyName:  fyne.KeyB},
+			want: altWant(asciiEscape, 'B'),
+		},
+		"Control+Alt+A": {
+			shortcut: &desktop.CustomShortcut{
+				Modifier: fyne.KeyModifierControl | fyne.KeyModifierAlt,
+				KeyName:  fyne.KeyA},
+			want: altWant(asciiEscape, 1),
+		},
+		"Control+Return": {
+			shortcut: &desktop.CustomShortcut{
+				Modifier: fyne.KeyModifierControl,
+				KeyName:  fyne.KeyReturn},
 			want: []byte{},
 		},
 		"Control+@": {
@@ -170,13 +198,37 @@ func TestTerminal_TypedShortcut(t *testing.T) {
 				KeyName:  fyne.KeyX},
 			want: []byte{24},
 		},
+		"Control+Left": {
+			shortcut: &desktop.CustomShortcut{
+				Modifier: fyne.KeyModifierControl,
+				KeyName:  fyne.KeyLeft},
+			want: []byte("\x1b[1;5D"),
+		},
+		"Alt+Shift+Home": {
+			shortcut: &desktop.CustomShortcut{
+				Modifier: fyne.KeyModifierAlt | fyne.KeyModifierShift,
+				KeyName:  fyne.KeyHome},
+			want: []byte("\x1b[1;4H"),
+		},
+		"Control+Alt+F5": {
+			shortcut: &desktop.CustomShortcut{
+				Modifier: fyne.KeyModifierControl | fyne.KeyModifierAlt,
+				KeyName:  fyne.KeyF5},
+			want: []byte("\x1b[15;7~"),
+		},
+		"Super+PageDown": {
+			shortcut: &desktop.CustomShortcut{
+				Modifier: fyne.KeyModifierSuper,
+				KeyName:  fyne.KeyPageDown},
+			want: []byte("\x1b[6;9~"),
+		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
 			// Creating a mock terminal
 			inBuffer := bytes.NewBuffer([]byte{})
-			term := &Terminal{in: NopCloser(inBuffer)}
+			term := &Terminal{in: NopCloser(inBuffer), screen: &Screen{}}
 
 			term.TypedShortcut(tt.shortcut)
 
@@ -187,3 +239,58 @@ func TestTerminal_TypedShortcut(t *testing.T) {
 		})
 	}
 }
+
+func TestTerminal_FocusReporting(t *testing.T) {
+	inBuffer := bytes.NewBuffer([]byte{})
+	term := New()
+	term.in = NopCloser(inBuffer)
+
+	term.FocusGained()
+	term.FocusLost()
+	assert.Equal(t, "", inBuffer.String())
+
+	_, _ = term.screen.Write([]byte("\x1b[?1004h"))
+	term.FocusGained()
+	term.FocusLost()
+	assert.Equal(t, "\x1b[I\x1b[O", inBuffer.String())
+
+	inBuffer.Reset()
+	_, _ = term.screen.Write([]byte("\x1b[?1004l"))
+	term.FocusGained()
+	assert.Equal(t, "", inBuffer.String())
+}
+
+func TestTerminal_AltKey(t *testing.T) {
+	inBuffer := bytes.NewBuffer([]byte{})
+	term := New()
+	term.in = NopCloser(inBuffer)
+	term.SetOptionAsAlt(true, false)
+	altF := &desktop.CustomShortcut{Modifier: fyne.KeyModifierAlt, KeyName: fyne.KeyF}
+
+	term.KeyDown(&fyne.KeyEvent{Name: desktop.KeyAltRight})
+	term.TypedShortcut(altF)
+	if runtime.GOOS == "darwin" {
+		assert.Equal(t, "", inBuffer.String()) // only the left Option key acts as Alt
+	}
+	term.KeyUp(&fyne.KeyEvent{Name: desktop.KeyAltRight})
+
+	inBuffer.Reset()
+	term.KeyDown(&fyne.KeyEvent{Name: desktop.KeyAltLeft})
+	term.TypedShortcut(altF)
+	assert.Equal(t, "\x1bf", inBuffer.String())
+
+	inBuffer.Reset()
+	term.SetAltSendsEscape(false)
+	term.TypedShortcut(altF)
+	assert.Equal(t, "f", inBuffer.String())
+
+	inBuffer.Reset()
+	_, _ = term.screen.Write([]byte("\x1b[?1034h"))
+	term.TypedShortcut(altF)
+	assert.Equal(t, "\u00e6", inBuffer.String())
+
+	inBuffer.Reset()
+	_, _ = term.screen.Write([]byte("\x1b[?1036h"))
+	term.TypedShortcut(&desktop.CustomShortcut{Modifier: fyne.KeyModifierAlt, KeyName: fyne.KeyBackspace})
+	assert.Equal(t, "\x1b\x08", inBuffer.String())
+}
@@ -0,0 +1,39 @@
+package widget
+
+import "fyne.io/fyne/v2/widget"
+
+// Hyperlink is a link that an application has attached to some text using OSC 8.
+type Hyperlink struct {
+	// ID is optional, it joins text that is printed separately into one link.
+	ID  string
+	URI string
+}
+
+// Same returns true if other is part of the same link as this one.
+func (h *Hyperlink) Same(other *Hyperlink) bool {
+	if h == nil || other == nil {
+		return false
+	}
+	return h == other || (h.ID != "" && h.ID == other.ID && h.URI == other.URI)
+}
+
+// CellHyperlink returns the link attached to a cell, or nil if there is none.
+func CellHyperlink(cell widget.TextGridCell) *Hyperlink {
+	switch s := cell.Style.(type) {
+	case *CellStyle:
+		return s.Hyperlink
+	case *TermTextGridStyle:
+		return s.Hyperlink
+	}
+	return nil
+}
+
+// SetHoveredLink underlines all of the text that is part of the link given, pass nil to remove the underline.
+func (t *TermGrid) SetHoveredLink(link *Hyperlink) {
+	if t.hoveredLink == link {
+		return
+	}
+
+	t.hoveredLink = link
+	t.refreshDecorations()
+}
@@ -0,0 +1,18 @@
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHyperlink_Same(t *testing.T) {
+	a := &Hyperlink{ID: "1", URI: "https://fyne.io"}
+	assert.True(t, a.Same(a))
+	assert.True(t, a.Same(&Hyperlink{ID: "1", URI: "https://fyne.io"}))
+	assert.False(t, a.Same(&Hyperlink{ID: "2", URI: "https://fyne.io"}))
+	assert.False(t, a.Same(nil))
+
+	anon := &Hyperlink{URI: "https://fyne.io"}
+	assert.False(t, anon.Same(&Hyperlink{URI: "https://fyne.io"}))
+}
@@ -0,0 +1,112 @@
+package widget
+
+import (
+	"image"
+	"image/draw"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// CellImage is a picture shown over a block of cells, such as a sixel graphic.
+// Each cell covered shows CellWidth by CellHeight pixels of the image, starting from the top left.
+type CellImage struct {
+	Image                 image.Image
+	CellWidth, CellHeight int
+
+	// ID and PlacementID identify images placed using the kitty graphics protocol, so they can be deleted.
+	ID, PlacementID uint32
+	// Z is the stacking order requested for the image, images with a negative Z are drawn below the text.
+	Z int
+}
+
+// SetCellImage shows part of an image in a cell, the text and colours of the cell are kept.
+func SetCellImage(cell *widget.TextGridCell, img *CellImage, col, row int, bitmask byte) {
+	if cell.Rune == 0 {
+		cell.Rune = ' '
+	}
+	style := *termStyle(cell, bitmask) // copy, in case the style is shared with other cells
+	style.Image, style.ImageCol, style.ImageRow = img, col, row
+	cell.Style = &style
+}
+
+// RemoveCellImage removes the image shown in a cell.
+func RemoveCellImage(cell *widget.TextGridCell) {
+	s, ok := cell.Style.(*TermTextGridStyle)
+	if !ok || s.Image == nil {
+		return
+	}
+
+	style := *s
+	style.Image = nil
+	cell.Style = &style
+}
+
+// drawUnderlay draws the images that are placed below the text.
+func (t *TermGrid) drawUnderlay(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	cell := t.cellSize()
+	if cell.Width <= 0 || t.Size().Width <= 0 {
+		return img
+	}
+
+	t.drawImages(img, cell, float32(w)/t.Size().Width, true)
+	return img
+}
+
+// drawImages draws the part of an image that covers each image cell, unless it is highlighted.
+// Only the images below the text are drawn if below is true, otherwise only those above it.
+// Where images overlap in a cell, only the last one placed is kept.
+func (t *TermGrid) drawImages(img *image.RGBA, cell fyne.Size, scale float32, below bool) {
+	for row, cols := range t.images {
+		if row >= len(t.Rows) {
+			continue
+		}
+
+		y0 := int(float32(row) * cell.Height * scale)
+		y1 := int(float32(row+1) * cell.Height * scale)
+		for _, col := range cols {
+			if col >= len(t.Rows[row].Cells) {
+				continue
+			}
+			s, ok := t.Rows[row].Cells[col].Style.(*TermTextGridStyle)
+			if !ok || s.Image == nil || s.Highlighted || (s.Image.Z < 0) != below {
+				continue
+			}
+
+			x0 := int(float32(col) * cell.Width * scale)
+			x1 := int(float32(col+1) * cell.Width * scale)
+			min := s.Image.Image.Bounds().Min.Add(image.Pt(s.ImageCol*s.Image.CellWidth, s.ImageRow*s.Image.CellHeight))
+			src := image.Rectangle{Min: min, Max: min.Add(image.Pt(s.Image.CellWidth, s.Image.CellHeight))}
+			drawScaled(img, image.Rect(x0, y0, x1, y1), s.Image.Image, src)
+		}
+	}
+}
+
+// drawScaled draws the src rectangle of an image over the dst rectangle, using the nearest pixel if they differ in size.
+// Pixels outside the bounds of the image are not drawn.
+func drawScaled(dst *image.RGBA, dr image.Rectangle, src image.Image, sr image.Rectangle) {
+	if dr.Dx() == sr.Dx() && dr.Dy() == sr.Dy() {
+		draw.Draw(dst, dr, src, sr.Min, draw.Over)
+		return
+	}
+	if dr.Empty() || sr.Empty() {
+		return
+	}
+
+	bounds := src.Bounds()
+	for y := dr.Min.Y; y < dr.Max.Y; y++ {
+		sy := sr.Min.Y + (y-dr.Min.Y)*sr.Dy()/dr.Dy()
+		for x := dr.Min.X; x < dr.Max.X; x++ {
+			p := image.Pt(sr.Min.X+(x-dr.Min.X)*sr.Dx()/dr.Dx(), sy)
+			if !p.In(bounds) {
+				continue
+			}
+			c := src.At(p.X, p.Y)
+			if _, _, _, a := c.RGBA(); a == 0 {
+				continue
+			}
+			dst.Set(x, y, c)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTermGrid_Images(t *testing.T) {
+	test.NewApp()
+	grid := NewTermGrid()
+	grid.Rows = []widget.TextGridRow{{}}
+	w := test.NewTempWindow(t, grid)
+	w.Resize(fyne.NewSize(100, 50))
+
+	red, blue := color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			src.Set(x, y, red)
+		}
+	}
+	src.Set(3, 1, blue)
+	cells := &CellImage{Image: src, CellWidth: 2, CellHeight: 2}
+	for col := 0; col < 2; col++ {
+		style := NewTermTextGridStyle(nil, nil, 0, false).(*TermTextGridStyle)
+		style.Image, style.ImageCol = cells, col
+		grid.Rows[0].Cells = append(grid.Rows[0].Cells, widget.TextGridCell{Rune: ' ', Style: style})
+	}
+	grid.RefreshRows([]int{0})
+	assert.Equal(t, map[int][]int{0: {0, 1}}, grid.images)
+	assert.False(t, grid.decorations.Hidden)
+
+	img := grid.drawDecorations(100, 50).(*image.RGBA)
+	cell := grid.cellSize()
+	assert.Equal(t, red, img.RGBAAt(1, 1))
+	assert.Equal(t, red, img.RGBAAt(int(cell.Width)+1, 1))
+	assert.Equal(t, blue, img.RGBAAt(int(2*cell.Width)-1, int(cell.Height)-1))
+	assert.Equal(t, color.RGBA{}, img.RGBAAt(int(2*cell.Width)+1, 1))
+
+	grid.Rows[0].Cells[0].Style.(*TermTextGridStyle).Highlighted = true
+	img = grid.drawDecorations(100, 50).(*image.RGBA)
+	assert.Equal(t, color.RGBA{}, img.RGBAAt(1, 1))
+}
+
+func TestDrawScaled(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.Set(1, 1, color.RGBA{G: 255, A: 255})
+	dst := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	drawScaled(dst, image.Rect(0, 0, 4, 4), src, image.Rect(0, 0, 2, 2))
+
+	assert.Equal(t, color.RGBA{}, dst.RGBAAt(1, 1))
+	assert.Equal(t, color.RGBA{G: 255, A: 255}, dst.RGBAAt(2, 2))
+	assert.Equal(t, color.RGBA{G: 255, A: 255}, dst.RGBAAt(3, 3))
+}
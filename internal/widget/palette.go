@@ -0,0 +1,81 @@
+package widget
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+)
+
+// Palette looks up the colours that cells refer to, so that changing it recolours text that is already shown.
+type Palette interface {
+	// IndexedColor returns one of the 256 numbered colours, or nil to use the theme foreground.
+	IndexedColor(index uint8) color.Color
+	// DefaultColors returns the colours for text and backgrounds without a colour set, nil to use the theme.
+	DefaultColors() (fg, bg color.Color)
+}
+
+// PaletteColor is a colour that is looked up in a Palette each time it is drawn.
+type PaletteColor struct {
+	Palette Palette
+	Index   uint8
+}
+
+// RGBA returns the current value of this colour in the palette.
+func (p PaletteColor) RGBA() (r, g, b, a uint32) {
+	c := ResolveColor(p)
+	if c == nil {
+		return 0, 0, 0, 0
+	}
+	return c.RGBA()
+}
+
+// ResolveColor returns the colour that c currently refers to if it is a PaletteColor, otherwise c.
+func ResolveColor(c color.Color) color.Color {
+	if p, ok := c.(PaletteColor); ok {
+		if p.Palette == nil {
+			return nil
+		}
+		return p.Palette.IndexedColor(p.Index)
+	}
+	return c
+}
+
+// defaultColors returns the palette colours to use in place of nil, these may also be nil.
+func defaultColors(p Palette) (fg, bg color.Color) {
+	if p == nil {
+		return nil, nil
+	}
+	return p.DefaultColors()
+}
+
+// CellStyle is the style of cells that only have colours and a text style.
+// It is shared by many cells, so it is replaced by a TermTextGridStyle if one cell is highlighted.
+type CellStyle struct {
+	TextStyle        fyne.TextStyle
+	FGColor, BGColor color.Color
+	Palette          Palette // used for the colours that are not set, may be nil
+	Hyperlink        *Hyperlink
+}
+
+// Style is the text style a cell should use.
+func (c *CellStyle) Style() fyne.TextStyle {
+	return c.TextStyle
+}
+
+// TextColor returns the current text colour.
+func (c *CellStyle) TextColor() color.Color {
+	if c.FGColor == nil {
+		fg, _ := defaultColors(c.Palette)
+		return fg
+	}
+	return ResolveColor(c.FGColor)
+}
+
+// BackgroundColor returns the current background colour.
+func (c *CellStyle) BackgroundColor() color.Color {
+	if c.BGColor == nil {
+		_, bg := defaultColors(c.Palette)
+		return bg
+	}
+	return ResolveColor(c.BGColor)
+}
@@ -0,0 +1,57 @@
+package widget
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPalette struct {
+	colors [256]color.Color
+	fg, bg color.Color
+}
+
+func (p *testPalette) IndexedColor(index uint8) color.Color {
+	return p.colors[index]
+}
+
+func (p *testPalette) DefaultColors() (fg, bg color.Color) {
+	return p.fg, p.bg
+}
+
+func TestCellStyle_Palette(t *testing.T) {
+	p := &testPalette{}
+	p.colors[3] = color.White
+	style := &CellStyle{FGColor: PaletteColor{Palette: p, Index: 3}, Palette: p}
+
+	assert.Equal(t, color.White, style.TextColor())
+	assert.Nil(t, style.BackgroundColor())
+
+	p.colors[3] = color.Black
+	p.bg = color.White
+	assert.Equal(t, color.Black, style.TextColor())
+	assert.Equal(t, color.White, style.BackgroundColor())
+	assert.Nil(t, ResolveColor(PaletteColor{}))
+}
+
+func TestHighlightRange_SelectionColor(t *testing.T) {
+	test.NewApp()
+	p := &testPalette{}
+	p.colors[1] = color.White
+	shared := &CellStyle{FGColor: PaletteColor{Palette: p, Index: 1}, Palette: p}
+	grid := NewTermGrid()
+	grid.SelectionColor = color.Black
+	grid.Rows = []widget.TextGridRow{{Cells: []widget.TextGridCell{{Rune: 'A', Style: shared}, {Rune: 'B', Style: shared}}}}
+
+	HighlightRange(grid, false, 0, 0, 0, 0, 0xAA)
+	style := grid.Rows[0].Cells[0].Style.(*TermTextGridStyle)
+	assert.Equal(t, color.Black, style.BackgroundColor())
+	assert.Equal(t, color.White, style.TextColor())
+	assert.Same(t, shared, grid.Rows[0].Cells[1].Style)
+
+	p.colors[1] = color.Black
+	assert.Equal(t, color.Black, style.TextColor())
+}
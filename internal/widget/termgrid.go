@@ -2,9 +2,14 @@ package widget
 
 import (
 	"context"
+	"image"
+	"image/color"
+	"math"
 	"time"
 
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"fyne.io/fyne/v2"
@@ -17,6 +22,18 @@ const blinkingInterval = 500 * time.Millisecond
 type TermGrid struct {
 	widget.TextGrid
 
+	// SelectionColor is the background of highlighted cells, nil to invert their colours instead.
+	SelectionColor color.Color
+
+	blinking     map[int][]int         // the columns of cells that blink, by row
+	underlined   map[int][]int         // the columns of cells with underlines that we draw, by row
+	images       map[int][]int         // the columns of cells covered by an image, by row
+	blinkOff     bool                  // true while blinking cells are hidden
+	padded       []widget.TextGridCell // reused to blank the end of short rows
+	rendered     bool
+	decorations  *canvas.Raster // drawn over the text
+	underlay     *canvas.Raster // drawn between the background and the text, for images placed below it
+	hoveredLink  *Hyperlink
 	tickerCancel context.CancelFunc
 }
 
@@ -24,7 +41,15 @@ type TermGrid struct {
 func (t *TermGrid) CreateRenderer() fyne.WidgetRenderer {
 	t.ExtendBaseWidget(t)
 
-	return t.TextGrid.CreateRenderer()
+	t.rendered = true
+	t.decorations = canvas.NewRaster(t.drawDecorations)
+	t.decorations.Hidden = !t.hasDecorations()
+	t.underlay = canvas.NewRaster(t.drawUnderlay)
+	t.underlay.Hidden = len(t.images) == 0
+	text := t.TextGrid.CreateRenderer()
+	objects := append([]fyne.CanvasObject{t.underlay}, text.Objects()...)
+	objects = append(objects, t.decorations)
+	return &termGridRenderer{WidgetRenderer: text, grid: t, objects: objects}
 }
 
 // NewTermGrid creates a new empty TextGrid widget.
@@ -39,30 +64,239 @@ func NewTermGrid() *TermGrid {
 // Refresh will be called when this grid should update.
 // We update our blinking status and then call the TextGrid we extended to refresh too.
 func (t *TermGrid) Refresh() {
-	t.refreshBlink(false)
+	for row := range t.blinking {
+		delete(t.blinking, row)
+	}
+	for row := range t.underlined {
+		delete(t.underlined, row)
+	}
+	for row := range t.images {
+		delete(t.images, row)
+	}
+	for row := range t.Rows {
+		t.scanRow(row)
+	}
+	t.updateTicker()
+
+	fyne.Do(t.TextGrid.Refresh) // TODO fix root cause in refresh on wrong thread
 }
 
-func (t *TermGrid) refreshBlink(blink bool) {
-	// reset shouldBlink which can be set by setCellRune if a cell with BlinkEnabled is found
-	shouldBlink := false
+// RefreshRows redraws only the rows given, which is much faster than Refresh when output changes a few lines.
+// The rows must already exist in the grid, a full Refresh is needed if rows are removed or the grid is resized.
+func (t *TermGrid) RefreshRows(rows []int) {
+	if !t.rendered {
+		t.Refresh()
+		return
+	}
+
+	decorated := false
+	for _, row := range rows {
+		if row < 0 || row >= len(t.Rows) {
+			continue
+		}
+
+		had := t.decorated(row)
+		delete(t.blinking, row)
+		delete(t.underlined, row)
+		delete(t.images, row)
+		t.scanRow(row)
+		t.refreshRow(row)
+		decorated = decorated || had || t.decorated(row)
+	}
+	t.updateTicker()
+	if decorated {
+		t.refreshDecorations()
+	}
+}
+
+// decorated returns true if the row has underlines or images that we draw.
+func (t *TermGrid) decorated(row int) bool {
+	_, underlined := t.underlined[row]
+	_, images := t.images[row]
+	return underlined || images
+}
+
+func (t *TermGrid) hasDecorations() bool {
+	return len(t.underlined) > 0 || len(t.images) > 0 || t.hoveredLink != nil
+}
+
+// scanRow adds the blinking, underlined and image cells of a row to our sets,
+// so the ticker and decorations do not need to search the whole grid for them.
+func (t *TermGrid) scanRow(row int) {
+	for col, c := range t.Rows[row].Cells {
+		s, ok := c.Style.(*TermTextGridStyle)
+		if !ok || s == nil {
+			continue
+		}
+
+		if s.BlinkEnabled {
+			if t.blinking == nil {
+				t.blinking = make(map[int][]int)
+			}
+			t.blinking[row] = append(t.blinking[row], col)
+			s.blink(t.blinkOff)
+		}
+		if s.UnderlineStyle != UnderlineNone {
+			if t.underlined == nil {
+				t.underlined = make(map[int][]int)
+			}
+			t.underlined[row] = append(t.underlined[row], col)
+		}
+		if s.Image != nil {
+			if t.images == nil {
+				t.images = make(map[int][]int)
+			}
+			t.images[row] = append(t.images[row], col)
+		}
+	}
+}
+
+// refreshRow redraws each cell of a row, and blanks any cells beyond the end of its content.
+func (t *TermGrid) refreshRow(row int) {
+	cells := t.Rows[row].Cells
+	cols := t.columns()
+	if len(cells) < cols {
+		t.padded = append(t.padded[:0], cells...)
+		for len(t.padded) < cols {
+			t.padded = append(t.padded, widget.TextGridCell{Rune: ' '})
+		}
+		t.Rows[row].Cells = t.padded
+		defer func() {
+			t.Rows[row].Cells = cells
+		}()
+	}
+
+	for col, c := range t.Rows[row].Cells {
+		t.TextGrid.SetCell(row, col, c)
+	}
+}
+
+func (t *TermGrid) columns() int {
+	cell := t.cellSize()
+	if cell.Width <= 0 {
+		return 0
+	}
+
+	return int(math.Ceil(float64(t.Size().Width / cell.Width)))
+}
+
+// cellSize returns the size of a character cell, rounded in the same way as the TextGrid.
+func (t *TermGrid) cellSize() fyne.Size {
+	cell := fyne.MeasureText("M", t.Theme().Size(theme.SizeNameText), fyne.TextStyle{Monospace: true})
+	return fyne.NewSize(float32(math.Round(float64(cell.Width))), float32(math.Round(float64(cell.Height))))
+}
 
-	for _, row := range t.Rows {
-		for _, r := range row.Cells {
-			if s, ok := r.Style.(*TermTextGridStyle); ok && s != nil && s.BlinkEnabled {
-				shouldBlink = true
+// drawDecorations draws images and the underlines that the text cannot, such as curly lines or ones in a different colour.
+func (t *TermGrid) drawDecorations(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	cell := t.cellSize()
+	if cell.Width <= 0 || t.Size().Width <= 0 {
+		return img
+	}
+
+	scale := float32(w) / t.Size().Width
+	t.drawImages(img, cell, scale, false)
+
+	thickness := int(math.Round(float64(scale)))
+	if thickness < 1 {
+		thickness = 1
+	}
+	for row, cols := range t.underlined {
+		if row >= len(t.Rows) {
+			continue
+		}
+
+		y := int((float32(row+1)*cell.Height)*scale) - 1 - thickness
+		for _, col := range cols {
+			if col >= len(t.Rows[row].Cells) {
+				continue
+			}
+			s, ok := t.Rows[row].Cells[col].Style.(*TermTextGridStyle)
+			if !ok || s.Invisible || s.blinked {
+				continue
+			}
+
+			c := s.UnderlineColor
+			if c == nil || s.Highlighted {
+				c = s.TextColor()
+			}
+			if c == nil {
+				c = theme.Color(theme.ColorNameForeground)
+			}
+			x0 := int(float32(col) * cell.Width * scale)
+			x1 := int(float32(col+1) * cell.Width * scale)
+			drawUnderline(img, s.UnderlineStyle, x0, x1, y, thickness, c)
+		}
+	}
+
+	if t.hoveredLink == nil {
+		return img
+	}
+	for row := range t.Rows {
+		y := int((float32(row+1)*cell.Height)*scale) - 1 - thickness
+		for col, c := range t.Rows[row].Cells {
+			if !t.hoveredLink.Same(CellHyperlink(c)) {
+				continue
+			}
+
+			fg := c.Style.TextColor()
+			if fg == nil {
+				fg = theme.Color(theme.ColorNameForeground)
+			}
+			x0 := int(float32(col) * cell.Width * scale)
+			x1 := int(float32(col+1) * cell.Width * scale)
+			drawUnderline(img, UnderlineSingle, x0, x1, y, thickness, fg)
+		}
+	}
+	return img
+}
 
+func (t *TermGrid) refreshDecorations() {
+	if t.decorations == nil {
+		return
+	}
+
+	t.decorations.Hidden = !t.hasDecorations()
+	t.decorations.Refresh()
+	t.underlay.Hidden = len(t.images) == 0
+	t.underlay.Refresh()
+}
+
+func (t *TermGrid) refreshBlink(blink bool) {
+	t.blinkOff = blink
+	if !t.rendered {
+		return
+	}
+	for row, cols := range t.blinking {
+		if row >= len(t.Rows) {
+			continue
+		}
+
+		cells := t.Rows[row].Cells
+		for _, col := range cols {
+			if col >= len(cells) {
+				continue
+			}
+			if s, ok := cells[col].Style.(*TermTextGridStyle); ok && s != nil {
 				s.blink(blink)
+				t.TextGrid.SetCell(row, col, cells[col])
 			}
 		}
 	}
-	fyne.Do(t.TextGrid.Refresh) // TODO fix root cause in refresh on wrong thread
+	if len(t.underlined) > 0 {
+		t.refreshDecorations()
+	}
+}
 
+func (t *TermGrid) updateTicker() {
+	shouldBlink := len(t.blinking) > 0
 	switch {
 	case shouldBlink && t.tickerCancel == nil:
 		t.runBlink()
 	case !shouldBlink && t.tickerCancel != nil:
 		t.tickerCancel()
 		t.tickerCancel = nil
+		t.blinkOff = false
 	}
 }
 
@@ -89,3 +323,25 @@ func (t *TermGrid) runBlink() {
 		}
 	}()
 }
+
+type termGridRenderer struct {
+	fyne.WidgetRenderer
+
+	grid    *TermGrid
+	objects []fyne.CanvasObject
+}
+
+func (r *termGridRenderer) Layout(s fyne.Size) {
+	r.WidgetRenderer.Layout(s)
+	r.grid.decorations.Resize(s)
+	r.grid.underlay.Resize(s)
+}
+
+func (r *termGridRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *termGridRenderer) Refresh() {
+	r.WidgetRenderer.Refresh()
+	r.grid.refreshDecorations()
+}
@@ -0,0 +1,91 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTermGrid_RefreshRows(t *testing.T) {
+	test.NewApp()
+	grid := NewTermGrid()
+	grid.Rows = []widget.TextGridRow{
+		{Cells: []widget.TextGridCell{{Rune: 'A'}, {Rune: 'B'}}},
+		{Cells: []widget.TextGridCell{{Rune: 'C'}}},
+	}
+	w := test.NewTempWindow(t, grid)
+	w.Resize(fyne.NewSize(100, 50))
+
+	blink := NewTermTextGridStyle(color.White, color.Black, 0, true)
+	grid.Rows[1].Cells = []widget.TextGridCell{{Rune: 'D'}, {Rune: 'E', Style: blink}}
+	grid.Rows[0].Cells = grid.Rows[0].Cells[:1]
+	grid.RefreshRows([]int{0, 1})
+
+	assert.Equal(t, map[int][]int{1: {1}}, grid.blinking)
+	assert.NotNil(t, grid.tickerCancel)
+	assert.Len(t, grid.Rows[0].Cells, 1) // padding to blank the old content is not kept
+	assert.Equal(t, "A\nDE", grid.Text())
+
+	grid.Rows[1].Cells = grid.Rows[1].Cells[:1]
+	grid.RefreshRows([]int{1})
+	assert.Empty(t, grid.blinking)
+	assert.Nil(t, grid.tickerCancel)
+}
+
+func TestTermGrid_RefreshBlink(t *testing.T) {
+	test.NewApp()
+	grid := NewTermGrid()
+	blink := NewTermTextGridStyle(color.White, color.Black, 0, true).(*TermTextGridStyle)
+	grid.Rows = []widget.TextGridRow{
+		{Cells: []widget.TextGridCell{{Rune: 'A'}}},
+		{Cells: []widget.TextGridCell{{Rune: 'B'}, {Rune: 'C', Style: blink}}},
+	}
+	test.NewTempWindow(t, grid)
+	grid.Refresh()
+	defer grid.tickerCancel()
+
+	assert.Equal(t, map[int][]int{1: {1}}, grid.blinking)
+	grid.refreshBlink(true)
+	assert.True(t, blink.blinked)
+	grid.refreshBlink(false)
+	assert.False(t, blink.blinked)
+}
+
+func TestTermGrid_Decorations(t *testing.T) {
+	test.NewApp()
+	grid := NewTermGrid()
+	grid.Rows = []widget.TextGridRow{{Cells: []widget.TextGridCell{{Rune: 'A'}}}}
+	w := test.NewTempWindow(t, grid)
+	w.Resize(fyne.NewSize(100, 50))
+	assert.True(t, grid.decorations.Hidden)
+
+	curly := NewTermTextGridStyle(nil, nil, 0, false).(*TermTextGridStyle)
+	curly.UnderlineStyle = UnderlineCurly
+	curly.UnderlineColor = color.RGBA{R: 255, A: 255}
+	grid.Rows[0].Cells = append(grid.Rows[0].Cells, widget.TextGridCell{Rune: 'B', Style: curly})
+	grid.RefreshRows([]int{0})
+	assert.Equal(t, map[int][]int{0: {1}}, grid.underlined)
+	assert.False(t, grid.decorations.Hidden)
+
+	img := grid.drawDecorations(100, 50).(*image.RGBA)
+	cell := grid.cellSize()
+	found := false
+	for x := int(cell.Width); x < int(2*cell.Width); x++ {
+		for y := 0; y < int(cell.Height); y++ {
+			if img.RGBAAt(x, y) == curly.UnderlineColor {
+				found = true
+			}
+		}
+	}
+	assert.True(t, found)
+	assert.Equal(t, color.RGBA{}, img.RGBAAt(int(cell.Width/2), int(cell.Height)-2))
+
+	grid.Rows[0].Cells = grid.Rows[0].Cells[:1]
+	grid.RefreshRows([]int{0})
+	assert.True(t, grid.decorations.Hidden)
+}
@@ -12,23 +12,38 @@ import (
 // if highlighting has previously been applied it is enabled
 func HighlightRange(t *TermGrid, blockMode bool, startRow, startCol, endRow, endCol int, bitmask byte) {
 	applyHighlight := func(cell *widget.TextGridCell) {
-		// Check if already highlighted
-		if h, ok := cell.Style.(*TermTextGridStyle); !ok {
-			if cell.Style != nil {
-				cell.Style = NewTermTextGridStyle(cell.Style.TextColor(), cell.Style.BackgroundColor(), bitmask, false)
-			} else {
-				cell.Style = NewTermTextGridStyle(nil, nil, bitmask, false)
-			}
-			cell.Style.(*TermTextGridStyle).Highlighted = true
-
-		} else {
-			h.Highlighted = true
-		}
+		h := termStyle(cell, bitmask)
+		h.Highlighted = true
+		h.SelectionColor = t.SelectionColor
 	}
 
 	forRange(t, blockMode, startRow, startCol, endRow, endCol, applyHighlight, nil)
 }
 
+// HighlightSearchRange applies the search match colours to the given range.
+// These are shown underneath any selection highlight and are removed using ClearSearchHighlight.
+func HighlightSearchRange(t *TermGrid, startRow, startCol, endRow, endCol int, bitmask byte, fg, bg color.Color) {
+	applySearch := func(cell *widget.TextGridCell) {
+		h := termStyle(cell, bitmask)
+		h.SearchTextColor = fg
+		h.SearchBackgroundColor = bg
+	}
+
+	forRange(t, false, startRow, startCol, endRow, endCol, applySearch, nil)
+}
+
+// ClearSearchHighlight removes the search match colours from every cell in the grid.
+func ClearSearchHighlight(t *TermGrid) {
+	for _, row := range t.Rows {
+		for i := range row.Cells {
+			if h, ok := row.Cells[i].Style.(*TermTextGridStyle); ok {
+				h.SearchTextColor = nil
+				h.SearchBackgroundColor = nil
+			}
+		}
+	}
+}
+
 // ClearHighlightRange disables the highlight style for the given range
 func ClearHighlightRange(t *TermGrid, blockMode bool, startRow, startCol, endRow, endCol int) {
 	clearHighlight := func(cell *widget.TextGridCell) {
@@ -165,6 +180,26 @@ func forRange(t *TermGrid, blockMode bool, startRow, startCol, endRow, endCol in
 	}
 }
 
+// termStyle returns the TermTextGridStyle of a cell, converting the existing style if required.
+func termStyle(cell *widget.TextGridCell, bitmask byte) *TermTextGridStyle {
+	if h, ok := cell.Style.(*TermTextGridStyle); ok {
+		return h
+	}
+
+	if c, ok := cell.Style.(*CellStyle); ok {
+		h := NewTermTextGridStyle(c.FGColor, c.BGColor, bitmask, false).(*TermTextGridStyle)
+		h.TextStyle, h.Palette, h.Hyperlink = c.TextStyle, c.Palette, c.Hyperlink
+		cell.Style = h
+	} else if cell.Style != nil {
+		style := cell.Style.Style()
+		cell.Style = NewTermTextGridStyle(cell.Style.TextColor(), cell.Style.BackgroundColor(), bitmask, false)
+		cell.Style.(*TermTextGridStyle).TextStyle = style
+	} else {
+		cell.Style = NewTermTextGridStyle(nil, nil, bitmask, false)
+	}
+	return cell.Style.(*TermTextGridStyle)
+}
+
 // TermTextGridStyle defines a style that can be original or highlighted.
 type TermTextGridStyle struct {
 	TextStyle               fyne.TextStyle
@@ -175,6 +210,28 @@ type TermTextGridStyle struct {
 	Highlighted             bool
 	BlinkEnabled            bool
 	blinked                 bool
+
+	// Dim, Invisible and Reversed are text attributes that change the colours drawn.
+	// Bold, italic, underline and strikethrough are set in the TextStyle.
+	Dim, Invisible, Reversed bool
+
+	// UnderlineStyle and UnderlineColor are drawn by the TermGrid, for underlines that the text cannot draw.
+	// A nil UnderlineColor uses the text colour.
+	UnderlineStyle UnderlineStyle
+	UnderlineColor color.Color
+
+	// Palette looks up PaletteColor values and the colours used in place of nil, it may be nil.
+	Palette Palette
+	// SelectionColor is the background drawn when highlighted, nil to invert the colours instead.
+	SelectionColor color.Color
+	// Hyperlink is the link that this cell is part of, if any.
+	Hyperlink *Hyperlink
+	// Image is drawn over this cell, if set. ImageCol and ImageRow are the position of the cell within the image.
+	Image              *CellImage
+	ImageCol, ImageRow int
+
+	// SearchTextColor and SearchBackgroundColor are set when this cell is part of a search match.
+	SearchTextColor, SearchBackgroundColor color.Color
 }
 
 // Style is the text style a cell should use.
@@ -184,27 +241,72 @@ func (h *TermTextGridStyle) Style() fyne.TextStyle {
 
 // TextColor returns the color of the text, depending on whether it is highlighted.
 func (h *TermTextGridStyle) TextColor() color.Color {
-	if h.Highlighted {
-		if h.blinked {
-			return h.InvertedBackgroundColor
+	if h.Highlighted && h.SelectionColor == nil {
+		inverted, invertedBG := h.InvertedTextColor, h.InvertedBackgroundColor
+		if h.Reversed {
+			inverted, invertedBG = invertedBG, inverted
+		}
+		if h.blinked || h.Invisible {
+			return invertedBG
 		}
-		return h.InvertedTextColor
+		return inverted
 	}
-	if h.blinked {
-		if h.OriginalBackgroundColor == nil {
+	if h.SearchBackgroundColor != nil {
+		return h.SearchTextColor
+	}
+
+	fg, bg := h.colors()
+	if h.blinked || h.Invisible {
+		if bg == nil {
 			return color.Transparent
 		}
-		return h.OriginalBackgroundColor
+		return bg
 	}
-	return h.OriginalTextColor
+	return fg
 }
 
 // BackgroundColor returns the background color, depending on whether it is highlighted.
 func (h *TermTextGridStyle) BackgroundColor() color.Color {
+	if h.Highlighted && h.SelectionColor != nil {
+		return h.SelectionColor
+	}
 	if h.Highlighted {
+		if h.Reversed {
+			return h.InvertedTextColor
+		}
 		return h.InvertedBackgroundColor
 	}
-	return h.OriginalBackgroundColor
+	if h.SearchBackgroundColor != nil {
+		return h.SearchBackgroundColor
+	}
+
+	_, bg := h.colors()
+	return bg
+}
+
+// colors returns the text and background colours with the reverse and dim attributes applied.
+func (h *TermTextGridStyle) colors() (fg, bg color.Color) {
+	fg, bg = ResolveColor(h.OriginalTextColor), ResolveColor(h.OriginalBackgroundColor)
+	defFG, defBG := defaultColors(h.Palette)
+	if fg == nil {
+		fg = defFG
+	}
+	if bg == nil {
+		bg = defBG
+	}
+	if h.Reversed {
+		if fg == nil {
+			fg = theme.Color(theme.ColorNameForeground)
+		}
+		if bg == nil {
+			bg = theme.Color(theme.ColorNameBackground)
+		}
+		fg, bg = bg, fg
+	}
+	if h.Dim {
+		fg = dimColor(fg, bg)
+	}
+	return fg, bg
 }
 
 func (h *TermTextGridStyle) blink(b bool) {
@@ -252,6 +354,26 @@ func NewTermTextGridStyle(fg, bg color.Color, bitmask byte, blinkEnabled bool) w
 	}
 }
 
+// dimColor blends a text colour half way to the background so it appears faint.
+// A nil colour means the theme default.
+func dimColor(fg, bg color.Color) color.Color {
+	if fg == nil {
+		fg = theme.Color(theme.ColorNameForeground)
+	}
+	if bg == nil {
+		bg = theme.Color(theme.ColorNameBackground)
+	}
+
+	r1, g1, b1, a1 := fg.RGBA()
+	r2, g2, b2, _ := bg.RGBA()
+	return color.RGBA64{
+		R: uint16((r1 + r2) / 2),
+		G: uint16((g1 + g2) / 2),
+		B: uint16((b1 + b2) / 2),
+		A: uint16(a1),
+	}
+}
+
 // invertColor inverts a color c with the given bitmask
 func invertColor(c color.Color, bitmask uint8) color.Color {
 	r, g, b, a := c.RGBA()
@@ -1,6 +1,7 @@
 package widget
 
 import (
+	"image/color"
 	"testing"
 
 	"fyne.io/fyne/v2/test"
@@ -129,3 +130,62 @@ func TestGetTextRange(t *testing.T) {
 		})
 	}
 }
+
+func TestHighlightSearchRange(t *testing.T) {
+	// start the test app for the purpose of the test
+	test.NewApp()
+	textGrid := NewTermGrid()
+	textGrid.Rows = []widget.TextGridRow{
+		{Cells: []widget.TextGridCell{{Rune: 'A'}, {Rune: 'B'}, {Rune: 'C'}}},
+		{Cells: []widget.TextGridCell{{Rune: 'D'}, {Rune: 'E'}, {Rune: 'F'}}},
+	}
+
+	HighlightSearchRange(textGrid, 0, 1, 0, 2, 0xAA, color.White, color.Black)
+	for col, want := range []bool{false, true, true} {
+		style, ok := textGrid.Rows[0].Cells[col].Style.(*TermTextGridStyle)
+		if ok != want {
+			t.Fatalf("unexpected search style at col=%d: got %v, want %v", col, ok, want)
+		}
+		if ok && style.BackgroundColor() != color.Black {
+			t.Errorf("unexpected search background at col=%d: %v", col, style.BackgroundColor())
+		}
+	}
+
+	HighlightRange(textGrid, false, 0, 1, 0, 1, 0xAA)
+	style := textGrid.Rows[0].Cells[1].Style.(*TermTextGridStyle)
+	if style.BackgroundColor() != style.InvertedBackgroundColor {
+		t.Errorf("selection should be shown over the search highlight")
+	}
+
+	ClearSearchHighlight(textGrid)
+	if textGrid.Rows[0].Cells[2].Style.(*TermTextGridStyle).SearchBackgroundColor != nil {
+		t.Errorf("search highlight was not cleared")
+	}
+}
+
+func TestTermTextGridStyle_Attributes(t *testing.T) {
+	test.NewApp()
+	fg, bg := color.RGBA{R: 200, A: 255}, color.RGBA{B: 100, A: 255}
+	style := NewTermTextGridStyle(fg, bg, 0xAA, false).(*TermTextGridStyle)
+
+	style.Reversed = true
+	if style.TextColor() != bg || style.BackgroundColor() != fg {
+		t.Errorf("reversed colours were not swapped")
+	}
+	style.Highlighted = true
+	if style.BackgroundColor() != style.InvertedTextColor {
+		t.Errorf("selection of reversed text should invert the reversed colours")
+	}
+
+	style.Highlighted, style.Reversed = false, false
+	style.Dim = true
+	r, _, _, _ := style.TextColor().RGBA()
+	if r != 100*0x101 {
+		t.Errorf("dim text should blend towards the background, got red %d", r>>8)
+	}
+
+	style.Invisible = true
+	if style.TextColor() != bg {
+		t.Errorf("invisible text should be drawn in the background colour")
+	}
+}
@@ -0,0 +1,57 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// UnderlineStyle is the shape of a line drawn under text, as set by SGR 4:x.
+type UnderlineStyle uint8
+
+const (
+	// UnderlineNone means the text is not underlined.
+	UnderlineNone UnderlineStyle = iota
+	// UnderlineSingle is a straight line.
+	UnderlineSingle
+	// UnderlineDouble is two straight lines.
+	UnderlineDouble
+	// UnderlineCurly is a wavy line, often used for spelling or diagnostic errors.
+	UnderlineCurly
+	// UnderlineDotted is a line of dots.
+	UnderlineDotted
+	// UnderlineDashed is a line of dashes.
+	UnderlineDashed
+)
+
+// drawUnderline draws an underline across the pixels from x0 to x1, with the bottom of the line at y.
+// The pattern for dotted and dashed lines is based on x so it continues across neighbouring cells.
+func drawUnderline(img *image.RGBA, style UnderlineStyle, x0, x1, y, thickness int, c color.Color) {
+	line := func(x, y int) {
+		for i := 0; i < thickness; i++ {
+			img.Set(x, y-i, c)
+		}
+	}
+
+	for x := x0; x < x1; x++ {
+		switch style {
+		case UnderlineSingle:
+			line(x, y)
+		case UnderlineDouble:
+			line(x, y)
+			line(x, y-2*thickness)
+		case UnderlineCurly:
+			period := float64(x1 - x0)
+			offset := math.Sin(2 * math.Pi * float64(x-x0) / period)
+			line(x, y-thickness-int(math.Round(offset*float64(thickness))))
+		case UnderlineDotted:
+			if (x/thickness)%2 == 0 {
+				line(x, y)
+			}
+		case UnderlineDashed:
+			if (x/(3*thickness))%2 == 0 {
+				line(x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package widget
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrawUnderline(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	for name, tt := range map[string]struct {
+		style UnderlineStyle
+		want  []string
+	}{
+		"single": {UnderlineSingle, []string{"        ", "        ", "xxxxxxxx"}},
+		"double": {UnderlineDouble, []string{"xxxxxxxx", "        ", "xxxxxxxx"}},
+		"dotted": {UnderlineDotted, []string{"        ", "        ", "x x x x "}},
+		"dashed": {UnderlineDashed, []string{"        ", "        ", "xxx   xx"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 8, 3))
+			drawUnderline(img, tt.style, 0, 8, 2, 1, red)
+
+			assert.Equal(t, tt.want, pixelRows(img))
+		})
+	}
+}
+
+func TestDrawUnderline_Curly(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 3))
+	drawUnderline(img, UnderlineCurly, 0, 8, 2, 1, color.White)
+
+	rows := pixelRows(img)
+	assert.Equal(t, byte('x'), rows[1][0])
+	assert.Equal(t, byte('x'), rows[0][2])
+	assert.Equal(t, byte('x'), rows[2][6])
+}
+
+func pixelRows(img *image.RGBA) []string {
+	var rows []string
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		row := make([]byte, img.Bounds().Dx())
+		for x := range row {
+			row[x] = ' '
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0 {
+				row[x] = 'x'
+			}
+		}
+		rows = append(rows, string(row))
+	}
+	return rows
+}
@@ -0,0 +1,164 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	_ "image/gif" // register the formats that inline images can use
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"strconv"
+	"strings"
+
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+	"golang.org/x/image/draw"
+)
+
+const maxInlineImageSize = 10000 // the largest width or height that an inline image is decoded or scaled to
+
+// handleOSCITerm handles the iTerm2 OSC 1337 extensions, of which only inline images are supported.
+func (s *Screen) handleOSCITerm(data string) {
+	if !strings.HasPrefix(data, "File=") {
+		if s.debug {
+			log.Println("Unsupported iTerm2 OSC", data)
+		}
+		return
+	}
+
+	args, payload, ok := strings.Cut(data[len("File="):], ":")
+	if !ok {
+		return
+	}
+	inline, preserve := false, true
+	width, height := "auto", "auto"
+	for _, arg := range strings.Split(args, ";") {
+		key, value, _ := strings.Cut(arg, "=")
+		switch key {
+		case "inline":
+			inline = value == "1"
+		case "preserveAspectRatio":
+			preserve = value != "0"
+		case "width":
+			width = value
+		case "height":
+			height = value
+		}
+	}
+	if !inline {
+		if s.debug {
+			log.Println("Ignoring iTerm2 file download")
+		}
+		return
+	}
+
+	img, err := decodeInlineImage(payload)
+	if err != nil {
+		if s.debug {
+			log.Println("Failed to decode inline image", err)
+		}
+		return
+	}
+	w, h := s.inlineImageSize(img.Bounds().Size(), width, height, preserve)
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if size := img.Bounds().Size(); size.X != w || size.Y != h {
+		scaled := image.NewNRGBA(image.Rect(0, 0, w, h))
+		draw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), img, img.Bounds(), draw.Src, nil)
+		img = scaled
+	}
+
+	startCol := s.cursorCol
+	cols, _ := s.placeImage(&widget2.CellImage{Image: img}, 0, 0)
+	s.cursorCol = startCol + cols
+	if s.cursorCol > int(s.config.Columns) {
+		s.cursorCol = int(s.config.Columns)
+	}
+}
+
+// inlineImageSize returns the size in pixels to show an image of the given size, for the width and height arguments
+// of an inline image. Images that are wider than the screen are shrunk to fit it.
+func (s *Screen) inlineImageSize(size image.Point, width, height string, preserve bool) (int, int) {
+	if size.X <= 0 || size.Y <= 0 {
+		return 0, 0
+	}
+	screenWidth := int(s.config.Columns) * s.cellSize.X
+	w := inlineImageDimension(width, s.cellSize.X, screenWidth)
+	h := inlineImageDimension(height, s.cellSize.Y, int(s.config.Rows)*s.cellSize.Y)
+
+	switch {
+	case w == 0 && h == 0:
+		w, h = size.X, size.Y
+	case !preserve:
+		if w == 0 {
+			w = size.X
+		}
+		if h == 0 {
+			h = size.Y
+		}
+	case w == 0:
+		w = size.X * h / size.Y
+	case h == 0:
+		h = size.Y * w / size.X
+	case w*size.Y > h*size.X: // fit inside the box given
+		w = size.X * h / size.Y
+	default:
+		h = size.Y * w / size.X
+	}
+
+	if w > screenWidth && screenWidth > 0 {
+		if preserve {
+			h = h * screenWidth / w
+		}
+		w = screenWidth
+	}
+	if h > maxInlineImageSize {
+		h = maxInlineImageSize
+	}
+	return w, h
+}
+
+// inlineImageDimension converts a width or height such as "10" cells, "100px", "50%" of the screen or "auto"
+// to pixels. For auto, or a value that cannot be read, 0 is returned.
+func inlineImageDimension(value string, cell, screen int) int {
+	var n int
+	var err error
+	switch {
+	case value == "auto":
+		return 0
+	case strings.HasSuffix(value, "px"):
+		n, err = strconv.Atoi(value[:len(value)-2])
+	case strings.HasSuffix(value, "%"):
+		n, err = strconv.Atoi(value[:len(value)-1])
+		n = n * screen / 100
+	default:
+		n, err = strconv.Atoi(value)
+		n *= cell
+	}
+	if err != nil || n < 0 {
+		return 0
+	}
+	if n > maxInlineImageSize {
+		return maxInlineImageSize
+	}
+	return n
+}
+
+func decodeInlineImage(payload string) (image.Image, error) {
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if conf.Width > maxInlineImageSize || conf.Height > maxInlineImageSize {
+		return nil, errors.New("image too large")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
@@ -0,0 +1,77 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineImageDimension(t *testing.T) {
+	for value, want := range map[string]int{
+		"auto":  0,
+		"3":     30,
+		"25px":  25,
+		"50%":   400,
+		"x":     0,
+		"-2":    0,
+		"9999%": maxInlineImageSize,
+	} {
+		assert.Equal(t, want, inlineImageDimension(value, 10, 800), value)
+	}
+}
+
+func TestScreen_InlineImageSize(t *testing.T) {
+	s := NewScreen(80, 24, nil)
+	size := image.Pt(200, 100)
+	for _, tt := range []struct {
+		width, height string
+		preserve      bool
+		w, h          int
+	}{
+		{"auto", "auto", true, 200, 100},
+		{"10", "auto", true, 100, 50},
+		{"auto", "100px", true, 200, 100},
+		{"50px", "50px", true, 50, 25},
+		{"50px", "50px", false, 50, 50},
+		{"auto", "10px", false, 200, 10},
+		{"100%", "auto", true, 800, 400},
+		{"2000px", "auto", true, 800, 400},
+	} {
+		w, h := s.inlineImageSize(size, tt.width, tt.height, tt.preserve)
+		assert.Equal(t, tt.w, w, tt.width+"x"+tt.height)
+		assert.Equal(t, tt.h, h, tt.width+"x"+tt.height)
+	}
+}
+
+func TestScreen_InlineImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	buf := &bytes.Buffer{}
+	assert.Nil(t, png.Encode(buf, img))
+	payload := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	s := NewScreen(10, 4, nil)
+	s.SetCellSize(2, 2)
+	_, _ = s.Write([]byte("ab\x1b]1337;File=name=eA==;inline=1;width=4px:" + payload + "\x07c"))
+
+	style, ok := s.Cell(0, 2).Style.(*widget2.TermTextGridStyle)
+	assert.True(t, ok)
+	assert.Equal(t, image.Rect(0, 0, 4, 2), style.Image.Image.Bounds())
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, color.NRGBAModel.Convert(style.Image.Image.At(0, 0)))
+	assert.NotNil(t, s.Cell(0, 3).Style.(*widget2.TermTextGridStyle).Image)
+	assert.Equal(t, 'c', s.Cell(0, 4).Rune)
+
+	_, _ = s.Write([]byte("\r\n\x1b]1337;File=inline=1;width=3;height=2:" + payload + "\x07d"))
+	style = s.Cell(2, 2).Style.(*widget2.TermTextGridStyle)
+	assert.Equal(t, image.Rect(0, 0, 6, 3), style.Image.Image.Bounds())
+	assert.Equal(t, 'd', s.Cell(2, 3).Rune)
+
+	_, _ = s.Write([]byte("\x1b]1337;File=width=3:" + payload + "\x07e"))
+	assert.Equal(t, 'e', s.Cell(2, 4).Rune)
+}
@@ -0,0 +1,448 @@
+package terminal
+
+import (
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// The progressive enhancements of the kitty keyboard protocol that an application can ask for.
+const (
+	kittyKeyDisambiguate = 1 << iota // modified keys and escape are sent as escape codes
+	kittyKeyEventTypes               // repeats and releases are reported
+	kittyKeyAlternates               // the shifted key is reported too
+	kittyKeyAllAsEscapes             // text is sent as escape codes as well
+	kittyKeyText                     // the text of a key is added to its escape code
+)
+
+// The event types of the kitty keyboard protocol.
+const (
+	kittyKeyPress = iota + 1
+	kittyKeyRepeat
+	kittyKeyRelease
+)
+
+// maxKittyKeyboardStack is how many flags can be pushed, the oldest are dropped beyond this.
+const maxKittyKeyboardStack = 16
+
+// csiKey is the escape code of a functional key, sent as CSI code final.
+type csiKey struct {
+	code  int
+	final byte
+}
+
+var kittyFunctionalKeys = map[fyne.KeyName]csiKey{
+	fyne.KeyEscape:    {27, 'u'},
+	fyne.KeyReturn:    {13, 'u'},
+	fyne.KeyEnter:     {13, 'u'},
+	fyne.KeyTab:       {9, 'u'},
+	fyne.KeyBackspace: {127, 'u'},
+	fyne.KeyInsert:    {2, '~'},
+	fyne.KeyDelete:    {3, '~'},
+	fyne.KeyLeft:      {1, 'D'},
+	fyne.KeyRight:     {1, 'C'},
+	fyne.KeyUp:        {1, 'A'},
+	fyne.KeyDown:      {1, 'B'},
+	fyne.KeyPageUp:    {5, '~'},
+	fyne.KeyPageDown:  {6, '~'},
+	fyne.KeyHome:      {1, 'H'},
+	fyne.KeyEnd:       {1, 'F'},
+	fyne.KeyF1:        {1, 'P'},
+	fyne.KeyF2:        {1, 'Q'},
+	fyne.KeyF3:        {13, '~'},
+	fyne.KeyF4:        {1, 'S'},
+	fyne.KeyF5:        {15, '~'},
+	fyne.KeyF6:        {17, '~'},
+	fyne.KeyF7:        {18, '~'},
+	fyne.KeyF8:        {19, '~'},
+	fyne.KeyF9:        {20, '~'},
+	fyne.KeyF10:       {21, '~'},
+	fyne.KeyF11:       {23, '~'},
+	fyne.KeyF12:       {24, '~'},
+
+	desktop.KeyShiftLeft:    {57441, 'u'},
+	desktop.KeyControlLeft:  {57442, 'u'},
+	desktop.KeyAltLeft:      {57443, 'u'},
+	desktop.KeySuperLeft:    {57444, 'u'},
+	desktop.KeyShiftRight:   {57447, 'u'},
+	desktop.KeyControlRight: {57448, 'u'},
+	desktop.KeyAltRight:     {57449, 'u'},
+	desktop.KeySuperRight:   {57450, 'u'},
+}
+
+// handleKittyKeyboard handles the CSI u sequences that push, pop, set and query the kitty keyboard flags.
+func (s *Screen) handleKittyKeyboard(msg string) {
+	stack := s.kittyKeyboardStack()
+	params := strings.Split(msg[1:], ";")
+	n, _ := strconv.Atoi(params[0])
+	switch msg[0] {
+	case '>':
+		if len(*stack) == maxKittyKeyboardStack {
+			*stack = (*stack)[1:]
+		}
+		*stack = append(*stack, n)
+	case '<':
+		if n < 1 {
+			n = 1
+		}
+		if n > len(*stack) {
+			n = len(*stack)
+		}
+		*stack = (*stack)[:len(*stack)-n]
+	case '=':
+		mode := 1
+		if len(params) > 1 {
+			mode, _ = strconv.Atoi(params[1])
+		}
+		if len(*stack) == 0 {
+			*stack = append(*stack, 0)
+		}
+		top := &(*stack)[len(*stack)-1]
+		switch mode {
+		case 1:
+			*top = n
+		case 2:
+			*top |= n
+		case 3:
+			*top &^= n
+		}
+	case '?':
+		_, _ = s.reply.Write([]byte("\x1b[?" + strconv.Itoa(s.kittyKeyboardFlags()) + "u"))
+	default:
+		if s.debug {
+			log.Println("Unrecognised keyboard escape", msg+"u")
+		}
+	}
+}
+
+// kittyKeyboardStack returns the keyboard flags pushed by the application, the main and alternate screens
+// each have their own.
+func (s *Screen) kittyKeyboardStack() *[]int {
+	if s.altBufferActive {
+		return &s.kittyKeyboardAlt
+	}
+	return &s.kittyKeyboard
+}
+
+// kittyKeyboardFlags returns the kitty keyboard protocol enhancements in use, 0 for legacy keyboard input.
+func (s *Screen) kittyKeyboardFlags() int {
+	stack := *s.kittyKeyboardStack()
+	if len(stack) == 0 {
+		return 0
+	}
+	return stack[len(stack)-1]
+}
+
+// handleModifyKeys handles CSI > 4;level m to set the xterm modifyOtherKeys level, and CSI ? 4 m to query it.
+// The other key modifier resources that xterm has are not supported.
+func (s *Screen) handleModifyKeys(msg string) {
+	params := strings.Split(msg[1:], ";")
+	if params[0] != "4" && (msg[0] == '?' || params[0] != "") {
+		if s.debug {
+			log.Println("Unsupported key modifier resource", msg+"m")
+		}
+		return
+	}
+
+	if msg[0] == '?' {
+		_, _ = s.reply.Write([]byte("\x1b[>4;" + strconv.Itoa(s.modifyOtherKeys) + "m"))
+		return
+	}
+	level := 0
+	if len(params) > 1 {
+		level, _ = strconv.Atoi(params[1])
+	}
+	if level < 0 || level > 2 {
+		level = 0
+	}
+	s.modifyOtherKeys = level
+}
+
+// typedModifyOtherKey sends a key typed with modifiers as CSI 27;modifiers;code ~ if the application asked for
+// modifyOtherKeys. At level 1 this is only for keys that would otherwise lose their modifiers, such as Ctrl+Return,
+// Ctrl+1 or Ctrl+Shift+A, at level 2 it is for all keys typed with modifiers.
+func (t *Terminal) typedModifyOtherKey(name fyne.KeyName, mods fyne.KeyModifier) bool {
+	if t.screen.modifyOtherKeys == 0 || mods == 0 {
+		return false
+	}
+
+	var code rune
+	switch name {
+	case fyne.KeyReturn, fyne.KeyEnter:
+		code = '\r'
+	case fyne.KeyTab:
+		if mods == fyne.KeyModifierShift {
+			return false // back tab is sent instead
+		}
+		code = '\t'
+	case fyne.KeyBackspace:
+		code = asciiBackspace
+	case fyne.KeyEscape:
+		code = asciiEscape
+	default:
+		var ok bool
+		if code, ok = kittyKeyCode(name); !ok || mods == fyne.KeyModifierShift {
+			return false // text typed with Shift is not changed
+		}
+		if mods&fyne.KeyModifierShift != 0 {
+			code = unicode.ToUpper(code)
+		}
+	}
+
+	if t.screen.modifyOtherKeys == 1 {
+		ctrl := mods &^ fyne.KeyModifierShift
+		if ctrl != fyne.KeyModifierControl || (mods == ctrl && legacyKeyBytes(name, ctrl) != nil) {
+			return false
+		}
+	}
+	_, _ = t.Write([]byte("\x1b[27;" + strconv.Itoa(modifierParam(mods)) + ";" + strconv.Itoa(int(code)) + "~"))
+	return true
+}
+
+// keypadKeysX11 are the final bytes of the SS3 sequences sent by the numeric keypad in application keypad mode,
+// by the scan code of each key. The keypad digits have the same key names as the main row, so only the scan codes
+// tell them apart. These are the X11 and Wayland key codes.
+var keypadKeysX11 = map[int]byte{
+	90: 'p', 87: 'q', 88: 'r', 89: 's', 83: 't', 84: 'u', 85: 'v', 79: 'w', 80: 'x', 81: 'y',
+	63: 'j', 86: 'k', 129: 'l', 82: 'm', 91: 'n', 106: 'o', 125: 'X', 104: 'M',
+}
+
+// keypadKeysDarwin are the keypad finals by the macOS virtual key code.
+var keypadKeysDarwin = map[int]byte{
+	0x52: 'p', 0x53: 'q', 0x54: 'r', 0x55: 's', 0x56: 't', 0x57: 'u', 0x58: 'v', 0x59: 'w', 0x5b: 'x', 0x5c: 'y',
+	0x43: 'j', 0x45: 'k', 0x4e: 'm', 0x41: 'n', 0x4b: 'o', 0x51: 'X', 0x4c: 'M',
+}
+
+// keypadKeysWindows are the keypad finals by the Windows scan code, where 0x100 marks an extended key.
+var keypadKeysWindows = map[int]byte{
+	0x52: 'p', 0x4f: 'q', 0x50: 'r', 0x51: 's', 0x4b: 't', 0x4c: 'u', 0x4d: 'v', 0x47: 'w', 0x48: 'x', 0x49: 'y',
+	0x37: 'j', 0x4e: 'k', 0x4a: 'm', 0x53: 'n', 0x135: 'o', 0x11c: 'M',
+}
+
+// keypadKey returns the final byte of the SS3 sequence that a key of the numeric keypad sends in application keypad
+// mode, or false if the key was not typed on the keypad.
+func keypadKey(e *fyne.KeyEvent) (byte, bool) {
+	keys := keypadKeysX11
+	switch runtime.GOOS {
+	case "darwin":
+		keys = keypadKeysDarwin
+	case "windows":
+		keys = keypadKeysWindows
+	}
+	if final, ok := keys[e.Physical.ScanCode]; ok {
+		return final, true
+	}
+	if e.Name == fyne.KeyEnter { // the only keypad key with a name of its own
+		return 'M', true
+	}
+	return 0, false
+}
+
+// typedKeypadKey sends a key of the numeric keypad as SS3 final if the application asked for application keypad
+// mode. The character typed with the key, if any, is dropped.
+func (t *Terminal) typedKeypadKey(e *fyne.KeyEvent, mods fyne.KeyModifier) bool {
+	if !t.screen.keypadApplication || mods != 0 {
+		return false
+	}
+	final, ok := keypadKey(e)
+	if !ok {
+		return false
+	}
+	t.keySent = e.Name != fyne.KeyEnter
+	_, _ = t.Write([]byte{asciiEscape, 'O', final})
+	return true
+}
+
+// typedKittyKey sends a functional key that was typed if the application asked for the kitty keyboard protocol.
+// It returns false if the key should be sent in the legacy encoding instead.
+func (t *Terminal) typedKittyKey(name fyne.KeyName, mods fyne.KeyModifier) bool {
+	flags := t.screen.kittyKeyboardFlags()
+	key, ok := kittyFunctionalKeys[name]
+	if flags == 0 || !ok || isModifierKey(name) {
+		return false
+	}
+
+	event := t.keyEvent(flags)
+	if flags&kittyKeyAllAsEscapes == 0 && mods == 0 {
+		switch name {
+		case fyne.KeyReturn, fyne.KeyEnter, fyne.KeyTab, fyne.KeyBackspace:
+			return false
+		case fyne.KeyEscape:
+			if flags&kittyKeyDisambiguate == 0 && event == kittyKeyPress {
+				return false
+			}
+		default:
+			if event == kittyKeyPress {
+				return false
+			}
+		}
+	}
+
+	_, _ = t.Write(csiKeySequence(key, 0, mods, event, 0))
+	return true
+}
+
+// typedKittyShortcut sends a key typed with Ctrl, Alt or Super if the application asked for keys to be disambiguated.
+func (t *Terminal) typedKittyShortcut(name fyne.KeyName, mods fyne.KeyModifier) bool {
+	flags := t.screen.kittyKeyboardFlags()
+	if flags&(kittyKeyDisambiguate|kittyKeyAllAsEscapes) == 0 {
+		return false
+	}
+	if _, ok := kittyFunctionalKeys[name]; ok {
+		return t.typedKittyKey(name, mods)
+	}
+
+	code, ok := kittyKeyCode(name)
+	if !ok {
+		return false
+	}
+	var shifted rune
+	if mods&fyne.KeyModifierShift != 0 && flags&kittyKeyAlternates != 0 && unicode.ToUpper(code) != code {
+		shifted = unicode.ToUpper(code)
+	}
+	_, _ = t.Write(csiKeySequence(csiKey{int(code), 'u'}, shifted, mods, t.keyEvent(flags), 0))
+	return true
+}
+
+// typedKittyRune sends text as escape codes if the application asked for all keys to be reported that way.
+func (t *Terminal) typedKittyRune(r rune) bool {
+	flags := t.screen.kittyKeyboardFlags()
+	if flags&kittyKeyAllAsEscapes == 0 {
+		return false
+	}
+
+	mods := t.heldModifiers()
+	code := unicode.ToLower(r)
+	if held, ok := kittyKeyCode(t.keyHeld); ok && mods&fyne.KeyModifierShift != 0 {
+		code = held // the unshifted key, such as 1 for !
+	}
+	var shifted, text rune
+	if flags&kittyKeyAlternates != 0 && code != r {
+		shifted = r
+	}
+	if flags&kittyKeyText != 0 {
+		text = r
+	}
+	_, _ = t.Write(csiKeySequence(csiKey{int(code), 'u'}, shifted, mods, t.keyEvent(flags), text))
+	return true
+}
+
+// pressedKittyModifier reports a modifier key being pressed, if the application asked for all keys.
+func (t *Terminal) pressedKittyModifier(name fyne.KeyName) {
+	flags := t.screen.kittyKeyboardFlags()
+	if flags&kittyKeyAllAsEscapes != 0 && isModifierKey(name) {
+		_, _ = t.Write(csiKeySequence(kittyFunctionalKeys[name], 0, t.heldModifiers(), t.keyEvent(flags), 0))
+	}
+}
+
+// releasedKittyKey reports a key being released, if the application asked for event types.
+func (t *Terminal) releasedKittyKey(name fyne.KeyName) {
+	flags := t.screen.kittyKeyboardFlags()
+	if flags&kittyKeyEventTypes == 0 {
+		return
+	}
+
+	key, ok := kittyFunctionalKeys[name]
+	if ok && flags&kittyKeyAllAsEscapes == 0 {
+		switch name {
+		case fyne.KeyReturn, fyne.KeyEnter, fyne.KeyTab, fyne.KeyBackspace:
+			return
+		}
+		if isModifierKey(name) { // these are only reported with all keys
+			return
+		}
+	} else if !ok {
+		code, ok := kittyKeyCode(name)
+		if !ok {
+			return
+		}
+		key = csiKey{int(code), 'u'}
+	}
+	_, _ = t.Write(csiKeySequence(key, 0, t.heldModifiers(), kittyKeyRelease, 0))
+}
+
+// keyEvent returns if a key that was typed is a press, or a repeat because it is typed again while held down.
+func (t *Terminal) keyEvent(flags int) int {
+	if flags&kittyKeyEventTypes == 0 {
+		return kittyKeyPress
+	}
+	if t.keyRepeating && t.keyHeld != "" {
+		return kittyKeyRepeat
+	}
+	t.keyRepeating = true
+	return kittyKeyPress
+}
+
+func isModifierKey(name fyne.KeyName) bool {
+	switch name {
+	case desktop.KeyShiftLeft, desktop.KeyShiftRight, desktop.KeyControlLeft, desktop.KeyControlRight,
+		desktop.KeyAltLeft, desktop.KeyAltRight, desktop.KeySuperLeft, desktop.KeySuperRight:
+		return true
+	}
+	return false
+}
+
+// csiKeySequence returns the escape code for a key, in the form CSI code:shifted ; modifiers:event ; text final.
+// Parameters that are not needed are left out, so without the kitty extras this is the xterm encoding
+// of a modified key, and keys with a letter final and no modifiers are sent as in the legacy encoding.
+func csiKeySequence(key csiKey, shifted rune, mods fyne.KeyModifier, event int, text rune) []byte {
+	b := []byte{asciiEscape, '['}
+	withMods := mods != 0 || event > kittyKeyPress || text != 0
+	if key.final == 'u' || key.final == '~' || withMods {
+		b = strconv.AppendInt(b, int64(key.code), 10)
+	}
+	if shifted != 0 {
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(shifted), 10)
+	}
+	if withMods {
+		b = append(b, ';')
+		b = strconv.AppendInt(b, int64(modifierParam(mods)), 10)
+		if event > kittyKeyPress {
+			b = append(b, ':')
+			b = strconv.AppendInt(b, int64(event), 10)
+		}
+	}
+	if text != 0 {
+		b = append(b, ';')
+		b = strconv.AppendInt(b, int64(text), 10)
+	}
+	return append(b, key.final)
+}
+
+// kittyKeyCode returns the code of a key that types text, which is the lower case character it types.
+func kittyKeyCode(name fyne.KeyName) (rune, bool) {
+	if name == fyne.KeySpace {
+		return ' ', true
+	}
+
+	r, size := utf8.DecodeRuneInString(string(name))
+	if r == utf8.RuneError || size != len(name) {
+		return 0, false
+	}
+	return unicode.ToLower(r), true
+}
+
+// modifierParam returns the modifiers held as the parameter used in escape codes, which is 1 if there are none.
+func modifierParam(mods fyne.KeyModifier) int {
+	param := 1
+	if mods&fyne.KeyModifierShift != 0 {
+		param += 1
+	}
+	if mods&fyne.KeyModifierAlt != 0 {
+		param += 2
+	}
+	if mods&fyne.KeyModifierControl != 0 {
+		param += 4
+	}
+	if mods&fyne.KeyModifierSuper != 0 {
+		param += 8
+	}
+	return param
+}
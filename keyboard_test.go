@@ -0,0 +1,206 @@
+package terminal
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreen_KittyKeyboardFlags(t *testing.T) {
+	reply := &bytes.Buffer{}
+	s := NewScreen(10, 2, reply)
+	_, _ = s.Write([]byte("\x1b[?u"))
+	assert.Equal(t, "\x1b[?0u", reply.String())
+
+	_, _ = s.Write([]byte("\x1b[>1u\x1b[>3u"))
+	assert.Equal(t, 3, s.kittyKeyboardFlags())
+	_, _ = s.Write([]byte("\x1b[=8;2u"))
+	assert.Equal(t, 11, s.kittyKeyboardFlags())
+	_, _ = s.Write([]byte("\x1b[=2;3u"))
+	assert.Equal(t, 9, s.kittyKeyboardFlags())
+
+	_, _ = s.Write([]byte("\x1b[?1049h"))
+	assert.Equal(t, 0, s.kittyKeyboardFlags())
+	_, _ = s.Write([]byte("\x1b[>4u\x1b[?1049l"))
+	assert.Equal(t, 9, s.kittyKeyboardFlags())
+
+	_, _ = s.Write([]byte("\x1b[<ux"))
+	assert.Equal(t, 1, s.kittyKeyboardFlags())
+	assert.Equal(t, 'x', s.Cell(0, 0).Rune)
+	_, _ = s.Write([]byte("\x1b[<5u"))
+	assert.Equal(t, 0, s.kittyKeyboardFlags())
+
+	for i := 0; i < maxKittyKeyboardStack+2; i++ {
+		_, _ = s.Write([]byte("\x1b[>1u"))
+	}
+	assert.Equal(t, maxKittyKeyboardStack, len(s.kittyKeyboard))
+}
+
+func TestCSIKeySequence(t *testing.T) {
+	assert.Equal(t, "\x1b[27u", string(csiKeySequence(csiKey{27, 'u'}, 0, 0, kittyKeyPress, 0)))
+	assert.Equal(t, "\x1b[A", string(csiKeySequence(csiKey{1, 'A'}, 0, 0, kittyKeyPress, 0)))
+	assert.Equal(t, "\x1b[1;5A", string(csiKeySequence(csiKey{1, 'A'}, 0, fyne.KeyModifierControl, kittyKeyPress, 0)))
+	assert.Equal(t, "\x1b[3;1:3~", string(csiKeySequence(csiKey{3, '~'}, 0, 0, kittyKeyRelease, 0)))
+	assert.Equal(t, "\x1b[97:65;2;65u", string(csiKeySequence(csiKey{97, 'u'}, 'A', fyne.KeyModifierShift, kittyKeyPress, 'A')))
+}
+
+func TestTerminal_KittyKeyboard(t *testing.T) {
+	in := &bytes.Buffer{}
+	term := New()
+	term.in = NopCloser(in)
+	_, _ = term.screen.Write([]byte("\x1b[>1u"))
+
+	term.TypedShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyI, Modifier: fyne.KeyModifierControl})
+	assert.Equal(t, "\x1b[105;5u", in.String())
+	in.Reset()
+	term.TypedKey(&fyne.KeyEvent{Name: fyne.KeyTab})
+	assert.Equal(t, "\t", in.String())
+	in.Reset()
+	term.TypedKey(&fyne.KeyEvent{Name: fyne.KeyEscape})
+	assert.Equal(t, "\x1b[27u", in.String())
+	in.Reset()
+	term.TypedShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyTab, Modifier: fyne.KeyModifierControl})
+	assert.Equal(t, "\x1b[9;5u", in.String())
+	in.Reset()
+	term.TypedRune('a')
+	assert.Equal(t, "a", in.String())
+
+	in.Reset()
+	term.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: fyne.KeyModifierControl}, func(fyne.Shortcut) {})
+	term.TypedShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: fyne.KeyModifierControl})
+	assert.Equal(t, "", in.String())
+}
+
+func TestTerminal_KittyKeyboardEvents(t *testing.T) {
+	in := &bytes.Buffer{}
+	term := New()
+	term.in = NopCloser(in)
+	_, _ = term.screen.Write([]byte("\x1b[>31u"))
+
+	term.KeyDown(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+	assert.Equal(t, "\x1b[57441;2u", in.String())
+	in.Reset()
+	term.KeyDown(&fyne.KeyEvent{Name: fyne.Key1})
+	term.TypedRune('!')
+	term.TypedRune('!')
+	assert.Equal(t, "\x1b[49:33;2;33u\x1b[49:33;2:2;33u", in.String())
+	in.Reset()
+	term.KeyUp(&fyne.KeyEvent{Name: fyne.Key1})
+	term.KeyUp(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+	assert.Equal(t, "\x1b[49;2:3u\x1b[57441;1:3u", in.String())
+
+	in.Reset()
+	term.KeyDown(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	term.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	term.KeyUp(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	assert.Equal(t, "\x1b[13u\x1b[13;1:3u", in.String())
+
+	in.Reset()
+	_, _ = term.screen.Write([]byte("\x1b[=2u"))
+	term.KeyDown(&fyne.KeyEvent{Name: fyne.KeyUp})
+	term.TypedKey(&fyne.KeyEvent{Name: fyne.KeyUp})
+	term.TypedKey(&fyne.KeyEvent{Name: fyne.KeyUp})
+	term.KeyUp(&fyne.KeyEvent{Name: fyne.KeyUp})
+	term.KeyDown(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	term.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	term.KeyUp(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	assert.Equal(t, "\x1b[A\x1b[1;1:2A\x1b[1;1:3A\r", in.String())
+}
+
+func TestTerminal_ModifyOtherKeys(t *testing.T) {
+	in := &bytes.Buffer{}
+	term := New()
+	term.in = NopCloser(in)
+	ctrl := func(name fyne.KeyName, mods fyne.KeyModifier) string {
+		in.Reset()
+		term.TypedShortcut(&desktop.CustomShortcut{KeyName: name, Modifier: fyne.KeyModifierControl | mods})
+		return in.String()
+	}
+	standard := func(s fyne.Shortcut) string {
+		in.Reset()
+		term.TypedShortcut(s)
+		return in.String()
+	}
+
+	_, _ = term.screen.Write([]byte("\x1b[>4;1m\x1b[?4m"))
+	assert.Equal(t, "\x1b[>4;1m", in.String())
+	assert.Equal(t, "\x01", ctrl(fyne.KeyA, 0))
+	if runtime.GOOS != "darwin" {
+		assert.Equal(t, "\x01", standard(&fyne.ShortcutSelectAll{}))
+	}
+	assert.Equal(t, "\x1b[27;6;65~", ctrl(fyne.KeyA, fyne.KeyModifierShift))
+	assert.Equal(t, "\x1b[27;5;49~", ctrl(fyne.Key1, 0))
+	assert.Equal(t, "\x1b[27;5;13~", ctrl(fyne.KeyReturn, 0))
+	assert.Equal(t, "\x1b[1;5A", ctrl(fyne.KeyUp, 0))
+
+	_, _ = term.screen.Write([]byte("\x1b[>4;2m"))
+	assert.Equal(t, "\x1b[27;5;97~", ctrl(fyne.KeyA, 0))
+	if runtime.GOOS != "darwin" {
+		assert.Equal(t, "\x1b[27;5;97~", standard(&fyne.ShortcutSelectAll{}))
+		assert.Equal(t, "\x1b[27;5;99~", standard(&fyne.ShortcutCopy{}))
+		assert.Equal(t, "\x1b[27;5;118~", standard(&fyne.ShortcutPaste{}))
+		assert.Equal(t, "\x1b[27;5;120~", standard(&fyne.ShortcutCut{}))
+		assert.Equal(t, "\x1b[27;5;121~", standard(&fyne.ShortcutRedo{}))
+		assert.Equal(t, "\x1b[27;5;122~", standard(&fyne.ShortcutUndo{}))
+		assert.Equal(t, "", standard(&fyne.ShortcutCut{Secondary: true}))
+	}
+	assert.Equal(t, "\x1b[27;7;97~", ctrl(fyne.KeyA, fyne.KeyModifierAlt))
+	in.Reset()
+	term.keyboardState.shiftPressed = true
+	term.TypedKey(&fyne.KeyEvent{Name: fyne.KeyReturn})
+	term.TypedKey(&fyne.KeyEvent{Name: fyne.KeyTab})
+	assert.Equal(t, "\x1b[27;2;13~\x1b[Z", in.String())
+	term.keyboardState.shiftPressed = false
+
+	_, _ = term.screen.Write([]byte("\x1b[>4m"))
+	assert.Equal(t, "\x01", ctrl(fyne.KeyA, 0))
+	if runtime.GOOS != "darwin" {
+		assert.Equal(t, "\x01", standard(&fyne.ShortcutSelectAll{}))
+	}
+	assert.Equal(t, 0, term.screen.modifyOtherKeys)
+}
+
+func TestTerminal_KeypadApplication(t *testing.T) {
+	in := &bytes.Buffer{}
+	term := New()
+	term.in = NopCloser(in)
+	keypad1 := &fyne.KeyEvent{Name: fyne.Key1, Physical: fyne.HardwareKey{ScanCode: 87}}
+	switch runtime.GOOS {
+	case "darwin":
+		keypad1.Physical.ScanCode = 0x53
+	case "windows":
+		keypad1.Physical.ScanCode = 0x4f
+	}
+	typed := func(e *fyne.KeyEvent, r rune) string {
+		in.Reset()
+		term.KeyDown(e)
+		term.TypedKey(e)
+		if r != 0 {
+			term.TypedRune(r)
+		}
+		term.KeyUp(e)
+		return in.String()
+	}
+
+	assert.Equal(t, "1", typed(keypad1, '1'))
+	assert.Equal(t, "\n", typed(&fyne.KeyEvent{Name: fyne.KeyEnter}, 0))
+
+	_, _ = term.screen.Write([]byte("\x1b="))
+	assert.True(t, term.screen.keypadApplication)
+	assert.Equal(t, "\x1bOq", typed(keypad1, '1'))
+	assert.Equal(t, "\x1bOM", typed(&fyne.KeyEvent{Name: fyne.KeyEnter}, 0))
+	assert.Equal(t, "1", typed(&fyne.KeyEvent{Name: fyne.Key1}, '1'))
+
+	_, _ = term.screen.Write([]byte("\x1b>"))
+	assert.False(t, term.screen.keypadApplication)
+	assert.Equal(t, "1", typed(keypad1, '1'))
+
+	_, _ = term.screen.Write([]byte("\x1b[?66h"))
+	assert.True(t, term.screen.keypadApplication)
+	_, _ = term.screen.Write([]byte("\x1b[?66l"))
+	assert.False(t, term.screen.keypadApplication)
+}
@@ -0,0 +1,522 @@
+package terminal
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+)
+
+const (
+	maxKittyImages    = 128               // the oldest stored images are dropped beyond this
+	maxKittyDataBytes = 256 * 1024 * 1024 // the largest image data that will be read or decompressed
+	maxKittyImageSize = 10000             // the largest width or height of an image
+)
+
+// maxKittyPayload is the longest base64 payload that the chunks of a transmission are gathered into.
+var maxKittyPayload = maxKittyDataBytes / 3 * 4
+
+// kittyGraphics keeps the images transmitted using the kitty graphics protocol until they are placed or deleted.
+type kittyGraphics struct {
+	images  map[uint32]image.Image
+	order   []uint32          // image IDs in the order they were stored, so the oldest can be dropped
+	numbers map[uint32]uint32 // the newest image ID for each image number
+	nextID  uint32
+
+	pending  *kittyCommand // the first chunk of a transmission that is still being received
+	payload  strings.Builder
+	dropping bool // the chunks of a transmission that was too large are ignored until the last one
+}
+
+// kittyCommand is the control data of a graphics command, such as "a=T,f=100,i=1", and its payload.
+type kittyCommand struct {
+	action, medium, compression, delete byte
+	format                              int
+	id, number, placement               uint32
+	width, height, size, offset         int // of raw pixel data and the region of a file to read
+	x, y, w, h                          int // the part of the image to show
+	cols, rows                          int
+	z                                   int
+	noCursorMove, more                  bool
+	quiet                               int
+
+	payload string
+}
+
+func parseKittyCommand(control string) *kittyCommand {
+	cmd := &kittyCommand{action: 't', medium: 'd', delete: 'a', format: 32}
+	for _, pair := range strings.Split(control, ",") {
+		if len(pair) < 3 || pair[1] != '=' {
+			continue
+		}
+
+		value := pair[2:]
+		n, _ := strconv.Atoi(value)
+		switch pair[0] {
+		case 'a':
+			cmd.action = value[0]
+		case 't':
+			cmd.medium = value[0]
+		case 'o':
+			cmd.compression = value[0]
+		case 'd':
+			cmd.delete = value[0]
+		case 'f':
+			cmd.format = n
+		case 'i':
+			cmd.id = uint32(n)
+		case 'I':
+			cmd.number = uint32(n)
+		case 'p':
+			cmd.placement = uint32(n)
+		case 's':
+			cmd.width = n
+		case 'v':
+			cmd.height = n
+		case 'S':
+			cmd.size = n
+		case 'O':
+			cmd.offset = n
+		case 'x':
+			cmd.x = n
+		case 'y':
+			cmd.y = n
+		case 'w':
+			cmd.w = n
+		case 'h':
+			cmd.h = n
+		case 'c':
+			cmd.cols = n
+		case 'r':
+			cmd.rows = n
+		case 'z':
+			cmd.z = n
+		case 'C':
+			cmd.noCursorMove = n == 1
+		case 'm':
+			cmd.more = n == 1
+		case 'q':
+			cmd.quiet = n
+		}
+	}
+	return cmd
+}
+
+// handleKittyGraphics handles a kitty graphics command, the code is the control data and payload separated by ';'.
+// See https://sw.kovidgoyal.net/kitty/graphics-protocol/ for details.
+func (s *Screen) handleKittyGraphics(code string) {
+	control, payload := code, ""
+	if i := strings.IndexByte(code, ';'); i >= 0 {
+		control, payload = code[:i], code[i+1:]
+	}
+	if s.kitty == nil {
+		s.kitty = &kittyGraphics{images: make(map[uint32]image.Image), numbers: make(map[uint32]uint32)}
+	}
+	k := s.kitty
+
+	cmd := parseKittyCommand(control)
+	if k.dropping {
+		k.dropping = cmd.more
+		return
+	}
+	if k.pending != nil { // later chunks only say if there is more to come
+		if k.payload.Len()+len(payload) > maxKittyPayload {
+			s.replyKitty(k.pending, "EFBIG:image data is too large")
+			k.pending, k.dropping = nil, cmd.more
+			k.payload.Reset()
+			return
+		}
+		k.payload.WriteString(payload)
+		if cmd.more {
+			return
+		}
+		cmd, k.pending = k.pending, nil
+		cmd.payload = k.payload.String()
+		k.payload.Reset()
+	} else if cmd.more {
+		if len(payload) > maxKittyPayload {
+			s.replyKitty(cmd, "EFBIG:image data is too large")
+			k.dropping = true
+			return
+		}
+		k.pending = cmd
+		k.payload.WriteString(payload)
+		return
+	} else {
+		cmd.payload = payload
+	}
+
+	switch cmd.action {
+	case 't', 'T', 'q':
+		img, err := s.loadKittyImage(cmd)
+		if err != nil {
+			s.replyKitty(cmd, err.Error())
+			return
+		}
+		if cmd.action == 'q' {
+			s.replyKitty(cmd, "OK")
+			return
+		}
+
+		identified := cmd.id != 0 || cmd.number != 0 // store allocates an ID, but anonymous images get no reply
+		k.store(cmd, img)
+		if cmd.action == 'T' {
+			s.placeKittyImage(cmd, img)
+		}
+		if identified {
+			s.replyKitty(cmd, "OK")
+		}
+	case 'p':
+		id := cmd.id
+		if id == 0 {
+			id = k.numbers[cmd.number]
+		}
+		img, ok := k.images[id]
+		if !ok {
+			s.replyKitty(cmd, "ENOENT:image not found")
+			return
+		}
+		cmd.id = id
+		s.placeKittyImage(cmd, img)
+		s.replyKitty(cmd, "OK")
+	case 'd':
+		s.deleteKittyImages(cmd)
+	default:
+		if s.debug {
+			log.Println("Unsupported kitty graphics action", string(cmd.action))
+		}
+		s.replyKitty(cmd, "EINVAL:unsupported action")
+	}
+}
+
+// loadKittyImage reads and decodes the image data of a command, returning an error that can be sent as a reply.
+func (s *Screen) loadKittyImage(cmd *kittyCommand) (image.Image, error) {
+	data, err := base64.StdEncoding.DecodeString(cmd.payload)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(cmd.payload, "="))
+	}
+	if err != nil {
+		return nil, errors.New("EINVAL:invalid base64 data")
+	}
+
+	switch cmd.medium {
+	case 'd':
+	case 'f', 't':
+		if !s.imageFiles {
+			return nil, errors.New("EBADF:files can only be read for a local shell")
+		}
+		data, err = readKittyFile(string(data), cmd.offset, cmd.size, cmd.medium == 't')
+		if err != nil {
+			return nil, fmt.Errorf("EBADF:%v", err)
+		}
+	default:
+		return nil, errors.New("EINVAL:unsupported transmission medium")
+	}
+
+	if cmd.compression == 'z' {
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.New("EINVAL:invalid compressed data")
+		}
+		data, err = io.ReadAll(io.LimitReader(r, maxKittyDataBytes))
+		if err != nil {
+			return nil, errors.New("EINVAL:invalid compressed data")
+		}
+	}
+
+	img, err := decodeKittyImage(cmd, data)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.x > 0 || cmd.y > 0 || cmd.w > 0 || cmd.h > 0 {
+		b := img.Bounds()
+		crop := image.Rect(b.Min.X+cmd.x, b.Min.Y+cmd.y, b.Max.X, b.Max.Y)
+		if cmd.w > 0 {
+			crop.Max.X = crop.Min.X + cmd.w
+		}
+		if cmd.h > 0 {
+			crop.Max.Y = crop.Min.Y + cmd.h
+		}
+		if sub, ok := img.(interface {
+			SubImage(image.Rectangle) image.Image
+		}); ok {
+			img = sub.SubImage(crop.Intersect(b))
+		}
+	}
+	return img, nil
+}
+
+func decodeKittyImage(cmd *kittyCommand, data []byte) (image.Image, error) {
+	if cmd.format == 100 {
+		conf, err := png.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.New("EBADPNG:could not decode PNG data")
+		}
+		if conf.Width > maxKittyImageSize || conf.Height > maxKittyImageSize {
+			return nil, errors.New("EINVAL:invalid image size")
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.New("EBADPNG:could not decode PNG data")
+		}
+		return img, nil
+	}
+
+	bytesPerPixel := 4
+	switch cmd.format {
+	case 24:
+		bytesPerPixel = 3
+	case 32:
+	default:
+		return nil, errors.New("EINVAL:unsupported format")
+	}
+	if cmd.width <= 0 || cmd.height <= 0 || cmd.width > maxKittyImageSize || cmd.height > maxKittyImageSize {
+		return nil, errors.New("EINVAL:invalid image size")
+	}
+	if len(data) < cmd.width*cmd.height*bytesPerPixel {
+		return nil, errors.New("ENODATA:insufficient image data")
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, cmd.width, cmd.height))
+	if bytesPerPixel == 4 {
+		copy(img.Pix, data)
+		return img, nil
+	}
+	for i := 0; i < cmd.width*cmd.height; i++ {
+		copy(img.Pix[i*4:i*4+3], data[i*3:i*3+3])
+		img.Pix[i*4+3] = 0xff
+	}
+	return img, nil
+}
+
+// readKittyFile reads the image data from a file, removing it afterwards if it is a temporary file for graphics.
+func readKittyFile(path string, offset, size int, temporary bool) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, errors.New("not a regular file")
+	}
+	if temporary {
+		tmp := filepath.Clean(os.TempDir()) + string(filepath.Separator)
+		if !strings.Contains(path, "tty-graphics-protocol") || !strings.HasPrefix(filepath.Clean(path), tmp) {
+			return nil, errors.New("not a temporary file")
+		}
+		defer os.Remove(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if _, err = f.Seek(int64(offset), io.SeekStart); err != nil {
+		return nil, err
+	}
+	limit := int64(maxKittyDataBytes)
+	if size > 0 && int64(size) < limit {
+		limit = int64(size)
+	}
+	return io.ReadAll(io.LimitReader(f, limit))
+}
+
+// store keeps an image so that it can be placed later, an ID is allocated if it only has a number.
+func (k *kittyGraphics) store(cmd *kittyCommand, img image.Image) {
+	if cmd.id == 0 {
+		for {
+			k.nextID++
+			if _, used := k.images[k.nextID]; !used && k.nextID != 0 {
+				break
+			}
+		}
+		cmd.id = k.nextID
+	}
+	if cmd.number != 0 {
+		k.numbers[cmd.number] = cmd.id
+	}
+
+	if _, exists := k.images[cmd.id]; !exists {
+		k.order = append(k.order, cmd.id)
+	}
+	k.images[cmd.id] = img
+	for len(k.order) > maxKittyImages {
+		delete(k.images, k.order[0])
+		k.order = k.order[1:]
+	}
+}
+
+func (k *kittyGraphics) remove(id uint32) {
+	delete(k.images, id)
+	for i, stored := range k.order {
+		if stored == id {
+			k.order = append(k.order[:i], k.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// placeKittyImage shows an image at the cursor, then moves the cursor after its last column on its last row,
+// unless the command asks for the cursor not to move.
+func (s *Screen) placeKittyImage(cmd *kittyCommand, img image.Image) {
+	startRow, startCol := s.cursorRow, s.cursorCol
+	ci := &widget2.CellImage{Image: img, ID: cmd.id, PlacementID: cmd.placement, Z: cmd.z}
+	cols, rows := cmd.cols, cmd.rows
+	if cols > int(s.config.Columns) { // columns beyond the right edge are lost anyway
+		cols = int(s.config.Columns)
+	}
+	maxRows := (img.Bounds().Dy() + s.cellSize.Y - 1) / s.cellSize.Y // the rows the image covers at its own size
+	if maxRows < int(s.config.Rows) {
+		maxRows = int(s.config.Rows) // but it can always be stretched to the height of the screen
+	}
+	if rows > maxRows {
+		rows = maxRows
+	}
+	cols, rows = s.placeImage(ci, cols, rows)
+
+	if cmd.noCursorMove {
+		scrolled := rows - 1 - (s.cursorRow - startRow)
+		row := startRow - scrolled
+		if row < 0 {
+			row = 0
+		}
+		s.moveCursor(row, startCol)
+		return
+	}
+
+	s.cursorCol = startCol + cols
+	if s.cursorCol > int(s.config.Columns) {
+		s.cursorCol = int(s.config.Columns)
+	}
+}
+
+// deleteKittyImages removes the placements chosen by the delete command from the screen.
+// An upper case delete also frees the stored data of the images whose placements are removed.
+func (s *Screen) deleteKittyImages(cmd *kittyCommand) {
+	at := func(row, col int) *widget2.CellImage {
+		if style, ok := s.Cell(row, col).Style.(*widget2.TermTextGridStyle); ok {
+			return style.Image
+		}
+		return nil
+	}
+	// positions are counted from 1
+	inColumn := func(col int, img *widget2.CellImage) bool {
+		for row := range s.content.Rows {
+			if at(row, col-1) == img {
+				return true
+			}
+		}
+		return false
+	}
+	inRow := func(row int, img *widget2.CellImage) bool {
+		for col := range s.content.Row(row - 1).Cells {
+			if at(row-1, col) == img {
+				return true
+			}
+		}
+		return false
+	}
+
+	id := cmd.id
+	var match func(img *widget2.CellImage) bool
+	switch cmd.delete {
+	case 'a', 'A':
+		match = func(*widget2.CellImage) bool { return true }
+	case 'n', 'N':
+		id = s.kitty.numbers[cmd.number]
+		fallthrough
+	case 'i', 'I':
+		match = func(img *widget2.CellImage) bool {
+			return img.ID == id && (cmd.placement == 0 || img.PlacementID == cmd.placement)
+		}
+	case 'c', 'C':
+		cursor := at(s.cursorRow, s.cursorCol)
+		match = func(img *widget2.CellImage) bool { return img == cursor }
+	case 'p', 'P':
+		cell := at(cmd.y-1, cmd.x-1)
+		match = func(img *widget2.CellImage) bool { return img == cell }
+	case 'q', 'Q':
+		cell := at(cmd.y-1, cmd.x-1)
+		match = func(img *widget2.CellImage) bool { return img == cell && img.Z == cmd.z }
+	case 'x', 'X':
+		match = func(img *widget2.CellImage) bool { return inColumn(cmd.x, img) }
+	case 'y', 'Y':
+		match = func(img *widget2.CellImage) bool { return inRow(cmd.y, img) }
+	case 'z', 'Z':
+		match = func(img *widget2.CellImage) bool { return img.Z == cmd.z }
+	case 'r', 'R':
+		match = func(img *widget2.CellImage) bool { return img.ID >= uint32(cmd.x) && img.ID <= uint32(cmd.y) }
+	default:
+		if s.debug {
+			log.Println("Unsupported kitty graphics delete", string(cmd.delete))
+		}
+		return
+	}
+
+	matched := make(map[*widget2.CellImage]bool)
+	for row, r := range s.content.Rows {
+		for col := range r.Cells {
+			img := at(row, col)
+			if img == nil {
+				continue
+			}
+			if _, ok := matched[img]; !ok {
+				matched[img] = match(img)
+			}
+		}
+	}
+
+	freed := make(map[uint32]bool)
+	if cmd.delete >= 'A' && cmd.delete <= 'Z' && id != 0 {
+		freed[id] = true
+	}
+	for row, r := range s.content.Rows {
+		changed := false
+		for col := range r.Cells {
+			img := at(row, col)
+			if img == nil || !matched[img] {
+				continue
+			}
+
+			widget2.RemoveCellImage(&r.Cells[col])
+			changed = true
+			if cmd.delete >= 'A' && cmd.delete <= 'Z' {
+				freed[img.ID] = true
+			}
+		}
+		if changed {
+			s.content.markDirty(row)
+		}
+	}
+	for id := range freed {
+		s.kitty.remove(id)
+	}
+}
+
+// replyKitty sends the result of a command, if it had an ID or number and the command did not ask to be quiet.
+func (s *Screen) replyKitty(cmd *kittyCommand, message string) {
+	if (cmd.id == 0 && cmd.number == 0) || cmd.quiet >= 2 || (cmd.quiet == 1 && message == "OK") {
+		return
+	}
+
+	keys := "i=" + strconv.FormatUint(uint64(cmd.id), 10)
+	if cmd.number != 0 {
+		keys += ",I=" + strconv.FormatUint(uint64(cmd.number), 10)
+	}
+	if cmd.placement != 0 {
+		keys += ",p=" + strconv.FormatUint(uint64(cmd.placement), 10)
+	}
+	_, _ = s.reply.Write([]byte("\x1b_G" + keys + ";" + message + "\x1b\\"))
+}
@@ -0,0 +1,182 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func cellImage(s *Screen, row, col int) *widget2.CellImage {
+	if style, ok := s.Cell(row, col).Style.(*widget2.TermTextGridStyle); ok {
+		return style.Image
+	}
+	return nil
+}
+
+func TestKittyGraphics_Transmit(t *testing.T) {
+	reply := &bytes.Buffer{}
+	s := NewScreen(10, 4, reply)
+	s.SetCellSize(2, 2)
+	rgb := base64.StdEncoding.EncodeToString([]byte{255, 0, 0, 0, 255, 0, 0, 0, 255})
+	_, _ = s.Write([]byte("x\x1b_Ga=T,f=24,s=3,v=1,i=7,z=-1;" + rgb + "\x1b\\y"))
+
+	assert.Equal(t, "\x1b_Gi=7;OK\x1b\\", reply.String())
+	img := cellImage(s, 0, 1)
+	assert.NotNil(t, img)
+	assert.Equal(t, uint32(7), img.ID)
+	assert.Equal(t, -1, img.Z)
+	assert.Equal(t, color.NRGBA{G: 255, A: 255}, img.Image.At(1, 0))
+	assert.Same(t, img, cellImage(s, 0, 2))
+	assert.Nil(t, cellImage(s, 0, 3))
+	assert.Equal(t, 'y', s.Cell(0, 3).Rune)
+
+	reply.Reset()
+	_, _ = s.Write([]byte("\x1b_Ga=q,f=24,s=3,v=1,i=8;" + rgb + "\x1b\\\x1b_Ga=q,f=24,s=3,v=2,i=9;" + rgb + "\x1b\\"))
+	assert.Equal(t, "\x1b_Gi=8;OK\x1b\\\x1b_Gi=9;ENODATA:insufficient image data\x1b\\", reply.String())
+	_, stored := s.kitty.images[8]
+	assert.False(t, stored)
+
+	reply.Reset()
+	_, _ = s.Write([]byte("\x1b_Ga=T,f=24,s=3,v=1;" + rgb + "\x1b\\"))
+	assert.Equal(t, "", reply.String())
+	assert.NotNil(t, cellImage(s, 0, 4))
+}
+
+func TestKittyGraphics_ChunkedPNG(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	src.Set(3, 3, color.NRGBA{B: 255, A: 255})
+	buf := &bytes.Buffer{}
+	_ = png.Encode(buf, src)
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	split := len(data) / 8 * 4
+
+	reply := &bytes.Buffer{}
+	s := NewScreen(10, 4, reply)
+	s.SetCellSize(2, 2)
+	_, _ = s.Write([]byte("\x1b_Ga=t,f=100,I=3,q=1,m=1;" + data[:split] + "\x1b\\\x1b_Gm=0;" + data[split:] + "\x1b\\"))
+	assert.Equal(t, "", reply.String())
+	id := s.kitty.numbers[3]
+	assert.NotZero(t, id)
+	assert.Nil(t, cellImage(s, 0, 0))
+
+	_, _ = s.Write([]byte("\x1b_Ga=p,I=3,p=2,c=4,r=1,C=1\x1b\\"))
+	assert.Equal(t, "\x1b_Gi="+strconv.Itoa(int(id))+",I=3,p=2;OK\x1b\\", reply.String())
+	img := cellImage(s, 0, 3)
+	assert.NotNil(t, img)
+	assert.Equal(t, uint32(2), img.PlacementID)
+	assert.Equal(t, 1, img.CellWidth)
+	assert.Equal(t, 4, img.CellHeight)
+	row, col := s.CursorPosition()
+	assert.Equal(t, 0, row)
+	assert.Equal(t, 0, col)
+}
+
+func TestDecodeKittyImage_TooLarge(t *testing.T) {
+	buf := &bytes.Buffer{}
+	_ = png.Encode(buf, image.NewGray(image.Rect(0, 0, maxKittyImageSize+1, 1)))
+
+	_, err := decodeKittyImage(&kittyCommand{format: 100}, buf.Bytes())
+	assert.EqualError(t, err, "EINVAL:invalid image size")
+	_, err = decodeKittyImage(&kittyCommand{format: 100}, buf.Bytes()[:20])
+	assert.EqualError(t, err, "EBADPNG:could not decode PNG data")
+}
+
+func TestKittyGraphics_ChunkLimit(t *testing.T) {
+	limit := maxKittyPayload
+	maxKittyPayload = 8
+	defer func() { maxKittyPayload = limit }()
+
+	reply := &bytes.Buffer{}
+	s := NewScreen(10, 4, reply)
+	_, _ = s.Write([]byte("\x1b_Ga=t,f=24,s=1,v=1,i=5,m=1;AAAA\x1b\\\x1b_Gm=1;AAAAAAAA\x1b\\"))
+	assert.Equal(t, "\x1b_Gi=5;EFBIG:image data is too large\x1b\\", reply.String())
+	assert.Nil(t, s.kitty.pending)
+	assert.Equal(t, 0, s.kitty.payload.Len())
+
+	reply.Reset()
+	_, _ = s.Write([]byte("\x1b_Gm=1;AAAA\x1b\\\x1b_Gm=0;AAAA\x1b\\"))
+	assert.Equal(t, "", reply.String())
+	_, _ = s.Write([]byte("\x1b_Ga=t,f=24,s=1,v=1,i=6;/wAA\x1b\\"))
+	assert.Equal(t, "\x1b_Gi=6;OK\x1b\\", reply.String())
+}
+
+func TestKittyGraphics_PlaceLimits(t *testing.T) {
+	s := NewScreen(10, 4, nil)
+	s.SetCellSize(2, 2)
+	pixel := base64.StdEncoding.EncodeToString([]byte{255, 0, 0})
+	_, _ = s.Write([]byte("\x1b_Ga=T,f=24,s=1,v=1,c=100000,r=500000000;" + pixel + "\x1b\\"))
+
+	img := cellImage(s, 0, 0)
+	assert.NotNil(t, img)
+	assert.NotNil(t, cellImage(s, 3, 9))
+	row, col := s.CursorPosition()
+	assert.Equal(t, 3, row)
+	assert.Equal(t, 10, col)
+}
+
+func TestKittyGraphics_Delete(t *testing.T) {
+	reply := &bytes.Buffer{}
+	s := NewScreen(10, 4, reply)
+	s.SetCellSize(1, 1)
+	rgba := base64.StdEncoding.EncodeToString(make([]byte, 2*4))
+	_, _ = s.Write([]byte("\x1b_Ga=T,s=2,v=1,i=1;" + rgba + "\x1b\\\x1b_Ga=p,i=1\x1b\\\r\n\x1b_Ga=T,s=2,v=1,i=2;" + rgba + "\x1b\\"))
+	assert.NotNil(t, cellImage(s, 0, 3))
+	assert.NotNil(t, cellImage(s, 1, 0))
+
+	_, _ = s.Write([]byte("\x1b_Ga=d,d=p,x=4,y=1\x1b\\"))
+	assert.NotNil(t, cellImage(s, 0, 0))
+	assert.Nil(t, cellImage(s, 0, 3))
+	assert.NotNil(t, cellImage(s, 1, 0))
+
+	_, _ = s.Write([]byte("\x1b_Ga=d,d=I,i=1\x1b\\"))
+	assert.Nil(t, cellImage(s, 0, 0))
+	assert.NotNil(t, cellImage(s, 1, 0))
+	reply.Reset()
+	_, _ = s.Write([]byte("\x1b_Ga=p,i=1\x1b\\"))
+	assert.Equal(t, "\x1b_Gi=1;ENOENT:image not found\x1b\\", reply.String())
+
+	_, _ = s.Write([]byte("\x1b_Ga=d\x1b\\"))
+	assert.Nil(t, cellImage(s, 1, 0))
+	_, stored := s.kitty.images[2]
+	assert.True(t, stored)
+}
+
+func TestKittyGraphics_File(t *testing.T) {
+	reply := &bytes.Buffer{}
+	s := NewScreen(10, 4, reply)
+	path := filepath.Join(os.TempDir(), "tty-graphics-protocol-test.rgba")
+	_ = os.WriteFile(path, make([]byte, 4), 0o600)
+	defer os.Remove(path)
+	file := base64.StdEncoding.EncodeToString([]byte(path))
+
+	_, _ = s.Write([]byte("\x1b_Ga=t,t=t,s=1,v=1,i=1;" + file + "\x1b\\"))
+	assert.Equal(t, "\x1b_Gi=1;EBADF:files can only be read for a local shell\x1b\\", reply.String())
+
+	reply.Reset()
+	s.imageFiles = true
+	_, _ = s.Write([]byte("\x1b_Ga=t,t=t,s=1,v=1,i=1;" + file + "\x1b\\"))
+	assert.Equal(t, "\x1b_Gi=1;OK\x1b\\", reply.String())
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	dir, err := os.MkdirTemp(filepath.Dir(filepath.Clean(os.TempDir())), filepath.Base(os.TempDir()))
+	if err != nil {
+		t.Skip("cannot create a directory next to the temporary directory")
+	}
+	defer os.RemoveAll(dir)
+	path = filepath.Join(dir, "tty-graphics-protocol-test.rgba")
+	_ = os.WriteFile(path, make([]byte, 4), 0o600)
+	_, err = readKittyFile(path, 0, 0, true)
+	assert.EqualError(t, err, "not a temporary file")
+	_, err = os.Stat(path)
+	assert.Nil(t, err)
+}
@@ -1,9 +1,90 @@
 package terminal
 
 import (
+	"fmt"
+
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
 )
 
+// maxMouseLegacyPosition is the largest column or row that fits in a byte of the default mouse encoding.
+const maxMouseLegacyPosition = 255 - 32
+
+// buttons after the first three are reported from 64, starting with the wheel
+const (
+	mouseWheelUp   = 65
+	mouseWheelDown = 66
+)
+
+// mouseButton returns the number of a button in mouse reports, or 0 if it cannot be reported.
+func mouseButton(b desktop.MouseButton) int {
+	switch b {
+	case desktop.MouseButtonPrimary:
+		return 1
+	case desktop.MouseButtonTertiary:
+		return 2
+	case desktop.MouseButtonSecondary:
+		return 3
+	}
+	return 0
+}
+
+// mouseDown reports a button press to the application if it requested mouse events.
+func (t *Terminal) mouseDown(btn int, mods fyne.KeyModifier, pos fyne.Position) {
+	switch t.screen.mouseMode {
+	case mouseModeX10:
+		t.handleMouseDownX10(btn, mods, pos)
+	case mouseModeV200, mouseModeButton, mouseModeAny:
+		t.handleMouseDownV200(btn, mods, pos)
+	}
+	t.mouseButton = btn
+	t.mouseCell = t.getTermPosition(pos)
+}
+
+// mouseUp reports a button release to the application if it requested mouse events.
+func (t *Terminal) mouseUp(btn int, mods fyne.KeyModifier, pos fyne.Position) {
+	switch t.screen.mouseMode {
+	case mouseModeX10:
+		t.handleMouseUpX10(btn, mods, pos)
+	case mouseModeV200, mouseModeButton, mouseModeAny:
+		t.handleMouseUpV200(btn, mods, pos)
+	}
+	t.mouseButton = 0
+}
+
+// mouseWheel reports the wheel being scrolled up, for a positive number of steps, or down.
+func (t *Terminal) mouseWheel(steps int, pos fyne.Position) {
+	btn := mouseWheelUp
+	if steps < 0 {
+		btn, steps = mouseWheelDown, -steps
+	}
+	for ; steps > 0; steps-- {
+		_, _ = t.Write(t.encodeMouse(btn, 0, pos))
+	}
+}
+
+// mouseMoved reports motion to the application if it asked for all motion,
+// or for motion while a button is held and one is.
+// Only moves to another cell are reported, unless the application asked for the position in pixels.
+func (t *Terminal) mouseMoved(mods fyne.KeyModifier, pos fyne.Position) {
+	switch t.screen.mouseMode {
+	case mouseModeAny:
+	case mouseModeButton:
+		if t.mouseButton == 0 {
+			return
+		}
+	default:
+		return
+	}
+
+	p := t.getTermPosition(pos)
+	if p == t.mouseCell && t.screen.mouseEncoding != mouseEncodingSGRPixels {
+		return
+	}
+	t.mouseCell = p
+	_, _ = t.Write(t.encodeMouseMotion(t.mouseButton, mods, pos))
+}
+
 func (t *Terminal) handleMouseDownV200(btn int, mods fyne.KeyModifier, pos fyne.Position) {
 	_, _ = t.Write(t.encodeMouse(btn, mods, pos))
 }
@@ -20,24 +101,77 @@ func (t *Terminal) handleMouseUpX10(_ int, _ fyne.KeyModifier, _ fyne.Position)
 	// no-op for X10 mode
 }
 
+// encodeMouse returns the report of a button being pressed, where 1 to 3 are the left, middle and right buttons,
+// or released if the button is 0.
 func (t *Terminal) encodeMouse(button int, mods fyne.KeyModifier, pos fyne.Position) []byte {
+	release := button == 0
+	if release && t.screen.mouseEncoding != mouseEncodingDefault && t.screen.mouseEncoding != mouseEncodingURXVT {
+		button = t.mouseButton // SGR reports say which button was released
+	}
+	return t.encodeMouseEvent(mouseButtonCode(button, mods), release, pos)
+}
+
+// encodeMouseMotion returns the report of the mouse moving while a button is held, or no button if it is 0.
+func (t *Terminal) encodeMouseMotion(button int, mods fyne.KeyModifier, pos fyne.Position) []byte {
+	return t.encodeMouseEvent(mouseButtonCode(button, mods)+32, false, pos)
+}
+
+func (t *Terminal) encodeMouseEvent(code int, release bool, pos fyne.Position) []byte {
 	p := t.getTermPosition(pos)
-	var btn byte
-	if button == 0 {
-		btn = 3
-	} else {
-		btn = byte(button) - 1
+	switch t.screen.mouseEncoding {
+	case mouseEncodingSGR, mouseEncodingSGRPixels:
+		final := 'M'
+		if release {
+			final = 'm'
+		}
+		x, y := p.Col, p.Row
+		if t.screen.mouseEncoding == mouseEncodingSGRPixels {
+			x, y = t.pixelPosition(pos)
+		}
+		return []byte(fmt.Sprintf("\x1b[<%d;%d;%d%c", code, x, y, final))
+	case mouseEncodingURXVT:
+		return []byte(fmt.Sprintf("\x1b[%d;%d;%dM", 32+code, p.Col, p.Row))
+	}
+
+	col, row := p.Col, p.Row
+	if col > maxMouseLegacyPosition {
+		col = maxMouseLegacyPosition
+	}
+	if row > maxMouseLegacyPosition {
+		row = maxMouseLegacyPosition
+	}
+	return []byte{asciiEscape, '[', 'M', byte(32 + code), byte(32 + col), byte(32 + row)}
+}
+
+// pixelPosition returns the position of the mouse in screen pixels, counted from 1.
+func (t *Terminal) pixelPosition(pos fyne.Position) (int, int) {
+	cell := t.guessCellSize()
+	x := int(pos.X/cell.Width*float32(t.screen.cellSize.X)) + 1
+	y := int(pos.Y/cell.Height*float32(t.screen.cellSize.Y)) + 1
+	if x < 1 {
+		x = 1
+	}
+	if y < 1 {
+		y = 1
+	}
+	return x, y
+}
+
+// mouseButtonCode returns the button number of a mouse report, including the modifiers held.
+func mouseButtonCode(button int, mods fyne.KeyModifier) int {
+	code := 3 // released, or no button
+	if button > 0 {
+		code = button - 1
 	}
 
 	if mods&fyne.KeyModifierShift != 0 {
-		btn += 4
+		code += 4
 	}
 	if mods&fyne.KeyModifierAlt != 0 {
-		btn += 8
+		code += 8
 	}
 	if mods&fyne.KeyModifierControl != 0 {
-		btn += 16
+		code += 16
 	}
-
-	return []byte{asciiEscape, '[', 'M', 32 + btn, 32 + byte(p.Col), 32 + byte(p.Row)}
+	return code
 }
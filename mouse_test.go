@@ -1,9 +1,11 @@
 package terminal
 
 import (
+	"bytes"
 	"testing"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,3 +27,77 @@ func TestEncodeMouse_Mods(t *testing.T) {
 	assert.Equal(t, "\x1b[M5!!", string(term.encodeMouse(2,
 		fyne.KeyModifierShift|fyne.KeyModifierControl, fyne.NewPos(4, 4))))
 }
+
+func TestEncodeMouse_Encodings(t *testing.T) {
+	term := New()
+	pos := fyne.NewPos(30, 36)
+	_, _ = term.screen.Write([]byte("\x1b[?1006h"))
+	term.mouseButton = 3
+	assert.Equal(t, "\x1b[<2;4;3M", string(term.encodeMouse(3, 0, pos)))
+	assert.Equal(t, "\x1b[<6;4;3m", string(term.encodeMouse(0, fyne.KeyModifierShift, pos)))
+	assert.Equal(t, "\x1b[<32;4;3M", string(term.encodeMouseMotion(1, 0, pos)))
+	assert.Equal(t, "\x1b[<35;4;3M", string(term.encodeMouseMotion(0, 0, pos)))
+
+	_, _ = term.screen.Write([]byte("\x1b[?1015h"))
+	assert.Equal(t, "\x1b[32;4;3M", string(term.encodeMouse(1, 0, pos)))
+	assert.Equal(t, "\x1b[35;4;3M", string(term.encodeMouse(0, 0, pos)))
+
+	_, _ = term.screen.Write([]byte("\x1b[?1016h"))
+	assert.Equal(t, "\x1b[<0;1;1M", string(term.encodeMouse(1, 0, fyne.NewPos(0, 0))))
+
+	_, _ = term.screen.Write([]byte("\x1b[?1006l"))
+	assert.Equal(t, mouseEncodingSGRPixels, term.screen.mouseEncoding)
+	_, _ = term.screen.Write([]byte("\x1b[?1016l"))
+	assert.Equal(t, mouseEncodingDefault, term.screen.mouseEncoding)
+	assert.Equal(t, "\x1b[M \xff\xff", string(term.encodeMouse(1, 0, fyne.NewPos(10000, 10000))))
+}
+
+func TestTerminal_MouseMotion(t *testing.T) {
+	reply := &bytes.Buffer{}
+	term := New()
+	term.in = NopCloser(reply)
+	move := func(x, y float32) {
+		term.MouseMoved(&desktop.MouseEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(x, y)}})
+	}
+
+	_, _ = term.screen.Write([]byte("\x1b[?1000h\x1b[?1006h"))
+	move(4, 4)
+	assert.Equal(t, "", reply.String())
+
+	_, _ = term.screen.Write([]byte("\x1b[?1002h"))
+	move(4, 4)
+	assert.Equal(t, "", reply.String())
+	term.MouseDown(&desktop.MouseEvent{Button: desktop.MouseButtonSecondary, PointEvent: fyne.PointEvent{Position: fyne.NewPos(4, 4)}})
+	assert.Equal(t, "\x1b[<2;1;1M", reply.String())
+	reply.Reset()
+	term.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(5, 5)}})
+	assert.Equal(t, "", reply.String()) // the same cell
+	term.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(30, 36)}})
+	assert.Equal(t, "\x1b[<34;4;3M", reply.String())
+	assert.False(t, term.selecting)
+	reply.Reset()
+	term.MouseUp(&desktop.MouseEvent{Button: desktop.MouseButtonSecondary, PointEvent: fyne.PointEvent{Position: fyne.NewPos(30, 36)}})
+	assert.Equal(t, "\x1b[<2;4;3m", reply.String())
+
+	reply.Reset()
+	_, _ = term.screen.Write([]byte("\x1b[?1003h"))
+	move(4, 4)
+	assert.Equal(t, "\x1b[<35;1;1M", reply.String())
+	_, _ = term.screen.Write([]byte("\x1b[?1003l"))
+	assert.Equal(t, mouseModeOff, term.screen.mouseMode)
+}
+
+func TestTerminal_MouseWheel(t *testing.T) {
+	reply := &bytes.Buffer{}
+	term := New()
+	term.in = NopCloser(reply)
+	_, _ = term.screen.Write([]byte("\x1b[?1000h"))
+
+	term.Scrolled(&fyne.ScrollEvent{Scrolled: fyne.Delta{DY: 1}, PointEvent: fyne.PointEvent{Position: fyne.NewPos(4, 4)}})
+	assert.Equal(t, "\x1b[M`!!", reply.String())
+	reply.Reset()
+	_, _ = term.screen.Write([]byte("\x1b[?1006h"))
+	term.Scrolled(&fyne.ScrollEvent{Scrolled: fyne.Delta{DY: -1}, PointEvent: fyne.PointEvent{Position: fyne.NewPos(4, 4)}})
+	assert.Equal(t, "\x1b[<65;1;1M", reply.String())
+	assert.Equal(t, 0, term.scrollOffset)
+}
@@ -1,29 +1,61 @@
 package terminal
 
 import (
+	"encoding/base64"
+	"fmt"
+	"image/color"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	widget2 "github.com/fyne-io/terminal/internal/widget"
 )
 
-func (t *Terminal) handleOSC(code string) {
-	if len(code) <= 2 || code[1] != ';' {
-		return
+func (s *Screen) handleOSC(code string) {
+	id, data := code, ""
+	if i := strings.IndexByte(code, ';'); i >= 0 {
+		id, data = code[:i], code[i+1:]
 	}
 
-	switch code[0] {
-	case '0':
+	switch id {
+	case "0":
 		// set icon name, if Fyne supports in the future
-		t.setTitle(code[2:])
-	case '1':
+		s.setTitle(data)
+	case "1":
 		// set icon name, if Fyne supports in the future
-	case '2':
-		t.setTitle(code[2:])
-	case '7':
-		t.setDirectory(code[2:])
+	case "2":
+		s.setTitle(data)
+	case "4":
+		s.handleOSCIndexedColors(data)
+	case "7":
+		if s.dirChanged != nil && data != "" {
+			s.dirChanged(data)
+		}
+	case "8":
+		s.handleOSCHyperlink(data)
+	case "9":
+		s.handleOSCNotification(data)
+	case "10", "11", "12":
+		s.handleOSCDynamicColors(id, data)
+	case "52":
+		s.handleOSCClipboard(data)
+	case "104":
+		s.resetIndexedColors(data)
+	case "110", "111", "112":
+		s.setDynamicColor(int(id[2]-'0'), s.basePalette.dynamicColor(int(id[2]-'0')))
+	case "133":
+		s.handleOSCShellIntegration(data)
+	case "1337":
+		s.handleOSCITerm(data)
+	case "777":
+		if args := strings.SplitN(data, ";", 3); args[0] == "notify" && len(args) == 3 {
+			s.notify(args[1], args[2])
+		}
 	default:
-		if t.debug {
+		if s.debug {
 			log.Println("Unrecognised OSC:", code)
 		}
 	}
@@ -50,7 +82,184 @@ func (t *Terminal) setDirectory(uri string) {
 	os.Chdir(u.Path())
 }
 
-func (t *Terminal) setTitle(title string) {
-	t.config.Title = title
-	t.onConfigure()
+// handleOSCHyperlink starts or ends a link, the data is a list of key=value parameters and the URI,
+// such as "id=1;https://fyne.io". An empty URI ends the link.
+func (s *Screen) handleOSCHyperlink(data string) {
+	i := strings.IndexByte(data, ';')
+	if i < 0 {
+		return
+	}
+	params, uri := data[:i], data[i+1:]
+	if uri == "" {
+		s.link = nil
+		return
+	}
+
+	id := ""
+	for _, param := range strings.Split(params, ":") {
+		if strings.HasPrefix(param, "id=") {
+			id = param[3:]
+		}
+	}
+	if s.link != nil && s.link.ID == id && s.link.URI == uri {
+		return // continue the same link
+	}
+	s.link = &widget2.Hyperlink{ID: id, URI: uri}
+}
+
+// handleOSCNotification shows the message of an iTerm2 style notification.
+// ConEmu uses the same code with a number and parameters for other commands, such as "4;1;50" for progress,
+// so these are ignored.
+func (s *Screen) handleOSCNotification(message string) {
+	command := message
+	if i := strings.IndexByte(message, ';'); i >= 0 {
+		command = message[:i]
+	}
+	if _, err := strconv.Atoi(command); err == nil {
+		if s.debug {
+			log.Println("Unsupported ConEmu OSC 9 command", message)
+		}
+		return
+	}
+
+	s.notify("", message)
+}
+
+func (s *Screen) notify(title, body string) {
+	if s.notified != nil {
+		s.notified(title, body)
+	}
+}
+
+// handleOSCClipboard sets or queries a clipboard, the data is a list of selections and either base64 text or "?".
+// The selection "c" is the clipboard and "p" or "s" the selection, an empty list means the clipboard.
+// A query reads the first selection listed, the reply is sent whenever the Terminal allows it.
+func (s *Screen) handleOSCClipboard(data string) {
+	i := strings.IndexByte(data, ';')
+	if i < 0 {
+		return
+	}
+	selections, text := data[:i], data[i+1:]
+	if selections == "" {
+		selections = "c"
+	}
+
+	if text == "?" {
+		if s.clipboardRead == nil {
+			return
+		}
+		sel, end := rune(selections[0]), s.oscEnd()
+		s.clipboardRead(sel, func(content string) {
+			encoded := base64.StdEncoding.EncodeToString([]byte(content))
+			_, _ = s.reply.Write([]byte("\x1b]52;" + string(sel) + ";" + encoded + end))
+		})
+		return
+	}
+
+	if s.clipboardWritten == nil {
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		if s.debug {
+			log.Println("Invalid clipboard data", err)
+		}
+		return
+	}
+	for _, sel := range selections {
+		s.clipboardWritten(sel, string(content))
+	}
+}
+
+// handleOSCIndexedColors sets or queries palette entries from a list of index and colour pairs, such as "1;#ff0000".
+func (s *Screen) handleOSCIndexedColors(data string) {
+	params := strings.Split(data, ";")
+	for i := 0; i+1 < len(params); i += 2 {
+		index, err := strconv.Atoi(params[i])
+		if err != nil || index < 0 || index > 255 {
+			if s.debug {
+				log.Println("Invalid palette index", params[i])
+			}
+			continue
+		}
+
+		if params[i+1] == "?" {
+			s.replyOSC(fmt.Sprintf("4;%d;%s", index, formatColorSpec(s.palette.Indexed[index], theme.ColorNameForeground)))
+			continue
+		}
+		if c, ok := parseColorSpec(params[i+1]); ok {
+			s.palette.Indexed[index] = c
+			s.content.allDirty = true
+		} else if s.debug {
+			log.Println("Invalid colour", params[i+1])
+		}
+	}
+}
+
+// handleOSCDynamicColors sets or queries the default foreground (10), background (11) and cursor (12) colours.
+// Each extra parameter applies to the next colour, so "10;?;?" queries both the foreground and background.
+func (s *Screen) handleOSCDynamicColors(id, data string) {
+	first := int(id[1] - '0')
+	for i, spec := range strings.Split(data, ";") {
+		which := first + i
+		if which > 2 {
+			break
+		}
+
+		if spec == "?" {
+			c := s.palette.dynamicColor(which)
+			s.replyOSC(fmt.Sprintf("1%d;%s", which, formatColorSpec(c, dynamicColorThemeNames[which])))
+			continue
+		}
+		if c, ok := parseColorSpec(spec); ok {
+			s.setDynamicColor(which, c)
+		} else if s.debug {
+			log.Println("Invalid colour", spec)
+		}
+	}
+}
+
+// resetIndexedColors restores the palette entries listed, or all of them if the list is empty.
+func (s *Screen) resetIndexedColors(data string) {
+	s.content.allDirty = true
+	if data == "" {
+		s.palette.Indexed = s.basePalette.Indexed
+		return
+	}
+
+	for _, param := range strings.Split(data, ";") {
+		if index, err := strconv.Atoi(param); err == nil && index >= 0 && index <= 255 {
+			s.palette.Indexed[index] = s.basePalette.Indexed[index]
+		}
+	}
+}
+
+func (s *Screen) setDynamicColor(which int, c color.Color) {
+	switch which {
+	case 0:
+		s.palette.Foreground = c
+	case 1:
+		s.palette.Background = c
+	case 2:
+		s.palette.Cursor = c
+	}
+	s.content.allDirty = true
+}
+
+// replyOSC sends an OSC response to the application, ending it the same way as the request.
+func (s *Screen) replyOSC(body string) {
+	_, _ = s.reply.Write([]byte("\x1b]" + body + s.oscEnd()))
+}
+
+// oscEnd returns the terminator of the OSC being handled, so that replies can end the same way.
+func (s *Screen) oscEnd() string {
+	if s.state != nil && s.state.oscBell {
+		return "\a"
+	}
+	return "\x1b\\"
+}
+
+func (s *Screen) setTitle(title string) {
+	s.config.Title = title
+	s.onConfigure()
 }
@@ -1,18 +1,112 @@
 package terminal
 
 import (
+	"bytes"
+	"fmt"
+	"image/color"
 	"testing"
 
+	"fyne.io/fyne/v2/theme"
+	widget2 "github.com/fyne-io/terminal/internal/widget"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestOSC_Title(t *testing.T) {
 	term := New()
-	assert.Equal(t, "", term.config.Title)
+	assert.Equal(t, "", term.screen.config.Title)
 
-	term.handleOSC("0;Test")
-	assert.Equal(t, "Test", term.config.Title)
+	term.screen.handleOSC("0;Test")
+	assert.Equal(t, "Test", term.screen.config.Title)
 
-	term.handleOSC("0;Testing;123")
-	assert.Equal(t, "Testing;123", term.config.Title)
+	term.screen.handleOSC("0;Testing;123")
+	assert.Equal(t, "Testing;123", term.screen.config.Title)
+}
+
+func TestOSC_IndexedColors(t *testing.T) {
+	reply := &bytes.Buffer{}
+	s := NewScreen(10, 1, reply)
+	_, _ = s.Write([]byte("\x1b[31mx\x1b]4;1;rgb:ff/80/0;2;#00f\x07"))
+	assert.Equal(t, color.RGBA64{R: 0xffff, G: 0x8080, A: 0xffff}, s.Cell(0, 0).Style.TextColor())
+	assert.Equal(t, color.RGBA64{B: 0xffff, A: 0xffff}, s.palette.Indexed[2])
+
+	_, _ = s.Write([]byte("\x1b]4;1;?\x1b\\"))
+	assert.Equal(t, "\x1b]4;1;rgb:ffff/8080/0000\x1b\\", reply.String())
+
+	reply.Reset()
+	_, _ = s.Write([]byte("\x1b]4;6;?\x07"))
+	assert.Equal(t, "\x1b]4;6;rgb:0000/aaaa/aaaa\x07", reply.String())
+
+	_, _ = s.Write([]byte("\x1b]104;1\x07"))
+	assert.Equal(t, basicColors[1], s.Cell(0, 0).Style.TextColor())
+	assert.Equal(t, color.RGBA64{B: 0xffff, A: 0xffff}, s.palette.Indexed[2])
+	_, _ = s.Write([]byte("\x1b]104\x07"))
+	assert.Equal(t, basicColors[2], s.palette.Indexed[2])
+}
+
+func TestOSC_DynamicColors(t *testing.T) {
+	reply := &bytes.Buffer{}
+	s := NewScreen(10, 1, reply)
+	_, _ = s.Write([]byte("x\x1b]10;#123456;rgb:ffff/ffff/ffff\x07\x1b]12;#f00\x07"))
+	assert.Equal(t, color.RGBA64{R: 0x1212, G: 0x3434, B: 0x5656, A: 0xffff}, s.Cell(0, 0).Style.TextColor())
+	assert.Equal(t, color.RGBA64{R: 0xffff, G: 0xffff, B: 0xffff, A: 0xffff}, s.palette.Background)
+	assert.Equal(t, color.RGBA64{R: 0xffff, A: 0xffff}, s.palette.Cursor)
+
+	_, _ = s.Write([]byte("\x1b]11;?\x07"))
+	assert.Equal(t, "\x1b]11;rgb:ffff/ffff/ffff\x07", reply.String())
+
+	_, _ = s.Write([]byte("\x1b]110\x07\x1b]111\x07"))
+	assert.Nil(t, s.Cell(0, 0).Style.TextColor())
+	assert.Nil(t, s.palette.Background)
+	assert.NotNil(t, s.palette.Cursor)
+
+	reply.Reset()
+	_, _ = s.Write([]byte("\x1b]10;?\x07"))
+	r, g, b, _ := theme.Color(theme.ColorNameForeground).RGBA()
+	assert.Equal(t, fmt.Sprintf("\x1b]10;rgb:%04x/%04x/%04x\x07", r, g, b), reply.String())
+}
+
+func TestParseColorSpec(t *testing.T) {
+	for spec, want := range map[string]color.Color{
+		"rgb:f/0/8":           color.RGBA64{R: 0xffff, B: 0x8888, A: 0xffff},
+		"rgb:ffff/0000/1234":  color.RGBA64{R: 0xffff, B: 0x1234, A: 0xffff},
+		"#ff0080":             color.RGBA64{R: 0xffff, B: 0x8080, A: 0xffff},
+		"#fff000000":          color.RGBA64{R: 0xffff, A: 0xffff},
+		"#ffff00000000":       color.RGBA64{R: 0xffff, A: 0xffff},
+		"red":                 nil,
+		"rgb:1/2":             nil,
+		"#12345":              nil,
+		"rgb:fffff/0000/0000": nil,
+	} {
+		c, ok := parseColorSpec(spec)
+		assert.Equal(t, want != nil, ok, spec)
+		assert.Equal(t, want, c, spec)
+	}
+}
+
+func TestOSC_Hyperlink(t *testing.T) {
+	s := NewScreen(20, 1, nil)
+	_, _ = s.Write([]byte("a\x1b]8;;https://fyne.io\x1b\\bc\x1b]8;;\x1b\\d\x1b]8;id=x:y=z;file:///a\x07e\x1b]8;;\x07f\x1b]8;id=x;file:///a\x07g"))
+
+	assert.Nil(t, widget2.CellHyperlink(s.Cell(0, 0)))
+	b := widget2.CellHyperlink(s.Cell(0, 1))
+	assert.Equal(t, &widget2.Hyperlink{URI: "https://fyne.io"}, b)
+	assert.Same(t, b, widget2.CellHyperlink(s.Cell(0, 2)))
+	assert.Nil(t, widget2.CellHyperlink(s.Cell(0, 3)))
+
+	e, g := widget2.CellHyperlink(s.Cell(0, 4)), widget2.CellHyperlink(s.Cell(0, 6))
+	assert.Equal(t, "x", e.ID)
+	assert.True(t, e.Same(g))
+	assert.False(t, e.Same(b))
+	assert.Nil(t, widget2.CellHyperlink(s.Cell(0, 5)))
+}
+
+func TestOSC_Notification(t *testing.T) {
+	term := New()
+	var got [][2]string
+	term.OnNotification = func(title, body string) {
+		got = append(got, [2]string{title, body})
+	}
+
+	_, _ = term.screen.Write([]byte("\x1b]9;Build done\x07\x1b]9;4;1;50\x07\x1b]777;notify;Make;All; done\x1b\\\x1b]777;other\x07"))
+	assert.Equal(t, [][2]string{{"", "Build done"}, {"Make", "All; done"}}, got)
 }
@@ -3,7 +3,6 @@ package terminal
 import (
 	"bytes"
 	"log"
-	"time"
 	"unicode/utf8"
 
 	"fyne.io/fyne/v2"
@@ -36,7 +35,7 @@ var charSetMap = map[charSet]func(rune) rune{
 	},
 }
 
-var specialChars = map[rune]func(t *Terminal){
+var specialChars = map[rune]func(s *Screen){
 	asciiBell:      handleOutputBell,
 	asciiBackspace: handleOutputBackspace,
 	'\n':           handleOutputLineFeed,
@@ -89,16 +88,15 @@ type parseState struct {
 	esc           int
 	escNext       bool // true when ESC was just seen; next char goes to parseEscState
 	osc, apc, dcs bool
+	oscBell       bool   // true if the OSC being handled ended with BEL, so replies should too
+	data          []byte // APC, DCS and OSC sequences can hold large images, so these are not added to code
 	vt100         rune
 	printing      bool
 }
 
-func (t *Terminal) handleOutput(buf []byte) []byte {
-	if t.hasSelectedText() {
-		t.clearSelectedText()
-	}
-	if t.state == nil {
-		t.state = &parseState{
+func (s *Screen) handleOutput(buf []byte) []byte {
+	if s.state == nil {
+		s.state = &parseState{
 			esc: noEscape,
 		}
 	}
@@ -115,289 +113,335 @@ func (t *Terminal) handleOutput(buf []byte) []byte {
 			break
 		}
 		if r == utf8.RuneError && size == 1 { // not UTF-8
-			if !t.state.printing {
-				if t.debug {
+			if !s.state.printing {
+				if !utf8.FullRune(buf) {
+					break // the rest of the character will be in the next buffer
+				}
+				if s.debug {
 					log.Println("Invalid UTF-8", buf[0])
 				}
 				continue
 			}
 		}
 
-		if t.state.printing {
-			t.parsePrinting(buf, size)
+		if s.state.printing {
+			s.parsePrinting(buf, size)
 			continue
 		}
 		if r == asciiEscape {
-			t.state.esc = i
-			t.state.escNext = true
+			s.state.esc = i
+			s.state.escNext = true
 			continue
 		}
-		if t.state.dcs {
-			t.parseDCS(r)
-			continue
-		}
-		if t.state.escNext {
-			t.state.escNext = false
-			if cont := t.parseEscState(r); cont {
+		if s.state.escNext {
+			s.state.escNext = false
+			if cont := s.parseEscState(r); cont {
 				continue
 			}
-			t.state.esc = noEscape
+			s.state.esc = noEscape
 			continue
 		}
-		if t.state.apc {
-			t.parseAPC(r)
+		if s.state.dcs {
+			s.parseDCS(r)
 			continue
 		}
-		if t.state.osc {
-			t.parseOSC(r)
+		if s.state.apc {
+			s.parseAPC(r)
 			continue
-		} else if t.state.vt100 != 0 {
-			t.handleVT100(string([]rune{t.state.vt100, r}))
-			t.state.vt100 = 0
+		}
+		if s.state.osc {
+			s.parseOSC(r)
 			continue
-		} else if t.state.esc != noEscape {
-			t.parseEscape(r)
+		} else if s.state.vt100 != 0 {
+			s.handleVT100(string([]rune{s.state.vt100, r}))
+			s.state.vt100 = 0
+			continue
+		} else if s.state.esc != noEscape {
+			s.parseEscape(r)
 			continue
 		}
 
-		if out, ok := specialChars[r]; ok {
-			if out == nil {
+		if r < ' ' { // only control characters can be special, skip the lookup for text
+			if out, ok := specialChars[r]; ok {
+				if out != nil {
+					out(s)
+				}
 				continue
 			}
-			fyne.Do(func() {
-				out(t)
-			})
+		}
+
+		// check to see which charset to use
+		if s.useG1CharSet {
+			s.handleOutputChar(charSetMap[s.g1Charset](r))
 		} else {
-			// check to see which charset to use
-			if t.useG1CharSet {
-				chr := charSetMap[t.g1Charset](r)
-				fyne.Do(func() {
-					t.handleOutputChar(chr)
-				})
-			} else {
-				chr := charSetMap[t.g0Charset](r)
-				fyne.Do(func() {
-					t.handleOutputChar(chr)
-				})
-			}
+			s.handleOutputChar(charSetMap[s.g0Charset](r))
 		}
 	}
 
 	return buf
 }
 
-func (t *Terminal) parseEscState(r rune) (shouldContinue bool) {
+func (s *Screen) parseEscState(r rune) (shouldContinue bool) {
 	switch r {
 	case '[':
 		return true
 	case '\\':
-		if t.state.osc {
-			code := t.state.code
-			fyne.Do(func() {
-				t.handleOSC(code)
-			})
+		if s.state.osc {
+			s.state.oscBell = false
+			s.endOSC()
+		} else if s.state.apc {
+			s.endAPC()
+		} else if s.state.dcs {
+			s.endDCS()
 		}
-		t.state.code = ""
-		t.state.osc = false
+		s.state.code = ""
+		s.state.osc = false
 	case ']':
-		t.state.osc = true
+		s.state.osc = true
 	case '(', ')':
-		t.state.vt100 = r
+		s.state.vt100 = r
 	case '7':
-		t.savedRow = t.cursorRow
-		t.savedCol = t.cursorCol
+		s.savedRow = s.cursorRow
+		s.savedCol = s.cursorCol
 	case '8':
-		t.cursorRow = t.savedRow
-		t.cursorCol = t.savedCol
+		s.cursorRow = s.savedRow
+		s.cursorCol = s.savedCol
 	case 'D':
-		t.scrollDown()
+		s.scrollDown()
 	case 'M':
-		t.scrollUp()
+		s.scrollUp()
 	case 'P':
-		t.state.dcs = true
+		s.state.dcs = true
 	case '_':
-		t.state.apc = true
-	case '=', '>':
+		s.state.apc = true
+	case '=':
+		s.keypadApplication = true
+	case '>':
+		s.keypadApplication = false
 	}
 	return false
 }
 
-func (t *Terminal) parseEscape(r rune) {
-	t.state.code += string(r)
-	if (r < '0' || r > '9') && r != ';' && r != '=' && r != '?' && r != '>' {
-		code := t.state.code
-		fyne.Do(func() {
-			t.handleEscape(code)
-		})
-		t.state.code = ""
-		t.state.esc = noEscape
+func (s *Screen) parseEscape(r rune) {
+	s.state.code += string(r)
+	if (r < '0' || r > '9') && r != ';' && r != ':' && r != '=' && r != '?' && r != '>' && r != '<' {
+		s.handleEscape(s.state.code)
+		s.state.code = ""
+		s.state.esc = noEscape
 	}
 }
 
-func (t *Terminal) parsePrinting(buf []byte, size int) {
-	t.printData = append(t.printData, buf[:size]...)
-	if bytes.HasSuffix(t.printData, []byte{asciiEscape, '[', '4', 'i'}) {
+func (s *Screen) parsePrinting(buf []byte, size int) {
+	s.printData = append(s.printData, buf[:size]...)
+	if bytes.HasSuffix(s.printData, []byte{asciiEscape, '[', '4', 'i'}) {
 		// Handle the end of printing
-		t.printData = t.printData[:len(t.printData)-4]
-		escapePrinterMode(t, "4")
-		t.state.esc = noEscape
+		s.printData = s.printData[:len(s.printData)-4]
+		escapePrinterMode(s, "4")
+		s.state.esc = noEscape
 	}
 }
 
-func (t *Terminal) parseAPC(r rune) {
+func (s *Screen) parseAPC(r rune) {
 	if r == 0 {
-		code := t.state.code
-		fyne.Do(func() {
-			t.handleAPC(code)
-		})
-		t.state.code = ""
-		t.state.apc = false
+		s.endAPC()
 	} else {
-		t.state.code += string(r)
+		s.state.data = utf8.AppendRune(s.state.data, r)
 	}
 }
 
-func (t *Terminal) parseOSC(r rune) {
-	if r == asciiBell || r == 0 {
-		code := t.state.code
-		fyne.Do(func() {
-			t.handleOSC(code)
-		})
-		t.state.code = ""
-		t.state.osc = false
-	} else {
-		t.state.code += string(r)
+// endAPC handles an APC sequence once it is terminated by ST or NUL.
+// Kitty graphics commands are handled by the Screen, others are passed on to the apcReceived hook.
+func (s *Screen) endAPC() {
+	code := string(s.state.data)
+	s.state.data = nil
+	s.state.apc = false
+
+	if len(code) > 0 && code[0] == 'G' {
+		s.handleKittyGraphics(code[1:])
+	} else if s.apcReceived != nil {
+		s.apcReceived(code)
+	} else if s.debug {
+		log.Println("Unrecognised APC", code)
 	}
 }
 
-func (t *Terminal) parseDCS(r rune) {
-	if r == '\\' {
-		code := t.state.code
-		fyne.Do(func() {
-			t.handleDCS(code)
-		})
-		t.state.code = ""
-		t.state.dcs = false
+func (s *Screen) parseOSC(r rune) {
+	if r == asciiBell || r == 0 {
+		s.state.oscBell = true
+		s.endOSC()
 	} else {
-		t.state.code += string(r)
+		s.state.data = utf8.AppendRune(s.state.data, r)
 	}
 }
 
-func (t *Terminal) handleOutputChar(r rune) {
-	if t.cursorCol == int(t.config.Columns) {
-		if !t.disableAutoWrap {
-			t.cursorCol = 0
-			handleOutputLineFeed(t)
+func (s *Screen) endOSC() {
+	code := string(s.state.data)
+	s.state.data = nil
+	s.state.osc = false
+	s.handleOSC(code)
+}
+
+func (s *Screen) parseDCS(r rune) {
+	s.state.data = utf8.AppendRune(s.state.data, r)
+}
+
+// endDCS handles a DCS sequence once it is terminated by ST.
+func (s *Screen) endDCS() {
+	code := string(s.state.data)
+	s.state.data = nil
+	s.state.dcs = false
+	s.handleDCS(code)
+}
+
+func (s *Screen) handleOutputChar(r rune) {
+	if s.cursorCol == int(s.config.Columns) {
+		if !s.disableAutoWrap {
+			if s.cursorRow < len(s.content.Rows) {
+				s.content.setWrapped(s.cursorRow, true)
+			}
+			s.cursorCol = 0
+			handleOutputLineFeed(s)
 		} else {
 			// In non-wrap mode, overwrite the last character
-			t.cursorCol = int(t.config.Columns) - 1
+			s.cursorCol = int(s.config.Columns) - 1
 		}
 	}
 
-	var cellStyle widget.TextGridStyle
-	cellStyle = &widget.CustomTextGridStyle{FGColor: t.currentFG, BGColor: t.currentBG}
-	if t.blinking {
-		cellStyle = widget2.NewTermTextGridStyle(t.currentFG, t.currentBG, highlightBitMask, t.blinking)
-	}
-
-	row, col := t.cursorRow, t.cursorCol
-	cell := widget.TextGridCell{Rune: r, Style: cellStyle}
+	row, col := s.cursorRow, s.cursorCol
+	cell := widget.TextGridCell{Rune: r, Style: s.cellStyle()}
 	oldLen := 0
-	if len(t.content.Rows) > row {
-		oldLen = len(t.content.Rows[row].Cells)
+	if len(s.content.Rows) > row {
+		oldLen = len(s.content.Rows[row].Cells)
 	}
-	t.content.SetCell(row, col, cell)
+	s.content.SetCell(row, col, cell)
 
 	for i := oldLen; i < col; i++ {
-		if t.content.Rows[row].Cells[i].Rune == 0 {
-			t.content.Rows[row].Cells[i].Rune = ' '
+		if s.content.Rows[row].Cells[i].Rune == 0 {
+			s.content.Rows[row].Cells[i].Rune = ' '
 		}
 	}
-	t.lastChar = r
-	t.cursorCol++
+	s.lastChar = r
+	s.cursorCol++
 }
 
-func (t *Terminal) ringBell() {
-	t.bell = true
-	t.Refresh()
+// cellStyle returns the style for new characters from the current colours and attributes.
+// Cells that only need colours and a text style share a style until these change,
+// rather than allocating one per character.
+func (s *Screen) cellStyle() widget.TextGridStyle {
+	fg := s.currentFG
+	if s.bold {
+		fg = brightColor(fg)
+	}
+	// a plain underline is drawn by the text, other styles and colours are drawn by the TermGrid
+	plainUnderline := s.underline == widget2.UnderlineSingle && s.underlineColor == nil
+	text := fyne.TextStyle{Bold: s.bold, Italic: s.italic, Underline: plainUnderline, Strikethrough: s.strikethrough}
+	if s.blinking || s.dim || s.inverse || s.invisible || (s.underline != widget2.UnderlineNone && !plainUnderline) {
+		style := widget2.NewTermTextGridStyle(fg, s.currentBG, highlightBitMask, s.blinking).(*widget2.TermTextGridStyle)
+		style.TextStyle = text
+		style.Dim, style.Invisible, style.Reversed = s.dim, s.invisible, s.inverse
+		style.Palette, style.Hyperlink = s.palette, s.link
+		if !plainUnderline {
+			style.UnderlineStyle, style.UnderlineColor = s.underline, s.underlineColor
+		}
+		return style
+	}
 
-	go func() {
-		time.Sleep(time.Millisecond * 300)
-		t.bell = false
-		fyne.Do(t.Refresh)
-	}()
+	if s.style == nil || s.style.FGColor != fg || s.style.BGColor != s.currentBG || s.style.TextStyle != text ||
+		s.style.Hyperlink != s.link {
+		s.style = &widget2.CellStyle{TextStyle: text, FGColor: fg, BGColor: s.currentBG, Palette: s.palette,
+			Hyperlink: s.link}
+	}
+	return s.style
 }
 
-func (t *Terminal) scrollUp() {
-	for i := t.scrollBottom; i > t.scrollTop; i-- {
-		t.content.Rows[i] = t.content.Row(i - 1)
+func (s *Screen) scrollUp() {
+	for i := s.scrollBottom; i > s.scrollTop; i-- {
+		s.content.Rows[i] = s.content.Row(i - 1)
+		s.content.setWrapped(i, s.content.isWrapped(i-1))
 	}
-	t.content.Rows[t.scrollTop] = widget.TextGridRow{}
-	t.content.Refresh()
+	s.content.Rows[s.scrollTop] = widget.TextGridRow{}
+	s.content.setWrapped(s.scrollTop, false)
+	s.content.markDirtyRange(s.scrollTop, s.scrollBottom)
 }
 
-func (t *Terminal) scrollDown() {
-	i := t.scrollTop
-	for ; i < t.scrollBottom && i < len(t.content.Rows)-1; i++ {
-		t.content.Rows[i] = t.content.Row(i + 1)
+func (s *Screen) scrollDown() {
+	if s.scrollTop == 0 && !s.altBufferActive && len(s.content.Rows) > 0 {
+		s.scrollback.push(s.content.Rows[0], s.content.isWrapped(0))
+		if s.historyPushed != nil {
+			s.historyPushed()
+		}
+	}
+
+	i := s.scrollTop
+	for ; i < s.scrollBottom && i < len(s.content.Rows)-1; i++ {
+		s.content.Rows[i] = s.content.Row(i + 1)
+		s.content.setWrapped(i, s.content.isWrapped(i+1))
 	}
-	for ; i < len(t.content.Rows); i++ {
-		if len(t.content.Rows) > t.scrollBottom {
-			t.content.Rows[t.scrollBottom] = widget.TextGridRow{}
+	for ; i < len(s.content.Rows); i++ {
+		if len(s.content.Rows) > s.scrollBottom {
+			s.content.Rows[s.scrollBottom] = widget.TextGridRow{}
+			s.content.setWrapped(s.scrollBottom, false)
 		} else {
-			t.content.Rows = append(t.content.Rows, widget.TextGridRow{})
+			s.content.Rows = append(s.content.Rows, widget.TextGridRow{})
 		}
 	}
-	t.content.Refresh()
+	s.content.markDirtyRange(s.scrollTop, s.scrollBottom)
 }
 
-func handleOutputBackspace(t *Terminal) {
-	row := t.content.Row(t.cursorRow)
+func handleOutputBackspace(s *Screen) {
+	row := s.content.Row(s.cursorRow)
 	if len(row.Cells) == 0 {
 		return
 	}
-	t.moveCursor(t.cursorRow, t.cursorCol-1)
+	s.moveCursor(s.cursorRow, s.cursorCol-1)
 }
 
-func handleOutputBell(t *Terminal) {
-	t.ringBell()
+func handleOutputBell(s *Screen) {
+	if s.bellRung != nil {
+		s.bellRung()
+	}
 }
 
-func handleOutputCarriageReturn(t *Terminal) {
-	t.moveCursor(t.cursorRow, 0)
+func handleOutputCarriageReturn(s *Screen) {
+	s.moveCursor(s.cursorRow, 0)
 }
 
-func handleOutputLineFeed(t *Terminal) {
-	if t.cursorRow == t.scrollBottom {
-		t.scrollDown()
-		if t.newLineMode {
-			t.moveCursor(t.cursorRow, 0)
+func handleOutputLineFeed(s *Screen) {
+	if s.cursorRow == s.scrollBottom {
+		s.scrollDown()
+		if s.newLineMode {
+			s.moveCursor(s.cursorRow, 0)
 		}
 		return
 	}
-	if t.newLineMode {
-		t.moveCursor(t.cursorRow+1, 0)
+	if s.newLineMode {
+		s.moveCursor(s.cursorRow+1, 0)
 		return
 	}
-	t.moveCursor(t.cursorRow+1, t.cursorCol)
+	s.moveCursor(s.cursorRow+1, s.cursorCol)
 }
 
-func handleOutputTab(t *Terminal) {
-	end := t.cursorCol - t.cursorCol%tabWidth + tabWidth
-	for t.cursorCol < end {
-		t.handleOutputChar(' ')
+func handleOutputTab(s *Screen) {
+	end := s.cursorCol - s.cursorCol%tabWidth + tabWidth
+	for s.cursorCol < end {
+		s.handleOutputChar(' ')
 	}
 }
 
-func handleShiftOut(t *Terminal) {
-	t.useG1CharSet = true
+func handleShiftOut(s *Screen) {
+	s.useG1CharSet = true
+}
+
+func handleShiftIn(s *Screen) {
+	s.useG1CharSet = false
 }
 
-func handleShiftIn(t *Terminal) {
-	t.useG1CharSet = false
+// SetPrinterFunc sets the printer function which is executed when printing.
+func (s *Screen) SetPrinterFunc(printerFunc PrinterFunc) {
+	s.printer = printerFunc
 }
 
 // SetPrinterFunc sets the printer function which is executed when printing.
 func (t *Terminal) SetPrinterFunc(printerFunc PrinterFunc) {
-	t.printer = printerFunc
+	t.screen.SetPrinterFunc(printerFunc)
 }
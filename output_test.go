@@ -1,6 +1,9 @@
 package terminal
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
 	"testing"
 
 	"fyne.io/fyne/v2"
@@ -11,11 +14,44 @@ import (
 func TestTerminal_Backspace(t *testing.T) {
 	term := New()
 	term.Resize(fyne.NewSize(50, 50))
-	term.handleOutput([]byte("Hi"))
-	assert.Equal(t, "Hi", term.content.Text())
+	term.screen.handleOutput([]byte("Hi"))
+	assert.Equal(t, "Hi", term.screen.content.Text())
 
-	term.handleOutput([]byte{asciiBackspace})
-	term.handleOutput([]byte("ello"))
+	term.screen.handleOutput([]byte{asciiBackspace})
+	term.screen.handleOutput([]byte("ello"))
 
-	assert.Equal(t, "Hello", term.content.Text())
+	assert.Equal(t, "Hello", term.screen.content.Text())
+}
+
+func BenchmarkTerminal_Output(b *testing.B) {
+	data := benchmarkOutput()
+	term := New()
+	term.Resize(fyne.NewSize(800, 600))
+	term.Refresh() // ensure visuals set up
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = term.RunWithConnection(discardWriter{}, bytes.NewReader(data))
+	}
+}
+
+func BenchmarkScreen_Write(b *testing.B) {
+	data := benchmarkOutput()
+	s := NewScreen(80, 24, nil)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = s.Write(data)
+	}
+}
+
+// benchmarkOutput returns about 1MB of text, like the output of cat on a source file with some colour.
+func benchmarkOutput() []byte {
+	var buf bytes.Buffer
+	for i := 0; buf.Len() < 1<<20; i++ {
+		fmt.Fprintf(&buf, "%5d \x1b[32mfunc\x1b[0m example(t *testing.T) { return \"%s\" }\r\n", i, strings.Repeat("x", i%40))
+	}
+	return buf.Bytes()
 }
@@ -0,0 +1,183 @@
+package terminal
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+)
+
+var (
+	basicColors = []color.Color{
+		color.Black,
+		&color.RGBA{170, 0, 0, 255},
+		&color.RGBA{0, 170, 0, 255},
+		&color.RGBA{170, 170, 0, 255},
+		&color.RGBA{0, 0, 170, 255},
+		&color.RGBA{170, 0, 170, 255},
+		&color.RGBA{0, 170, 170, 255},
+		&color.RGBA{170, 170, 170, 255},
+	}
+	brightColors = []color.Color{
+		&color.RGBA{85, 85, 85, 255},
+		&color.RGBA{255, 85, 85, 255},
+		&color.RGBA{85, 255, 85, 255},
+		&color.RGBA{255, 255, 85, 255},
+		&color.RGBA{85, 85, 255, 255},
+		&color.RGBA{255, 85, 255, 255},
+		&color.RGBA{85, 255, 255, 255},
+		&color.RGBA{255, 255, 255, 255},
+	}
+	colourBands = []uint8{
+		0x00,
+		0x5f,
+		0x87,
+		0xaf,
+		0xd7,
+		0xff,
+	}
+)
+
+// Palette is the set of colours that a terminal draws with.
+// Any colour that is nil uses the matching colour of the current theme.
+type Palette struct {
+	// Foreground and Background are used for text that has no colour set.
+	Foreground, Background color.Color
+	// Cursor is the colour of the cursor and Selection is the background of selected text.
+	Cursor, Selection color.Color
+
+	// Indexed holds the colours that applications select by number: 16 basic and bright colours,
+	// a 6x6x6 colour cube and 24 shades of grey.
+	Indexed [256]color.Color
+}
+
+// DefaultPalette returns a palette with the standard xterm indexed colours.
+// The foreground, background, cursor and selection colours are left to the theme.
+func DefaultPalette() *Palette {
+	p := &Palette{}
+	copy(p.Indexed[:8], basicColors)
+	copy(p.Indexed[8:16], brightColors)
+	for id := 16; id <= 231; id++ {
+		i := id - 16
+		b := i % 6
+		i = (i - b) / 6
+		g := i % 6
+		r := (i - g) / 6
+		p.Indexed[id] = &color.RGBA{colourBands[r], colourBands[g], colourBands[b], 255}
+	}
+	for id := 232; id <= 255; id++ {
+		inc := 256 / 24
+		p.Indexed[id] = &color.Gray{uint8((id - 232) * inc)}
+	}
+
+	return p
+}
+
+// SetPalette changes the colours used to draw the screen, including text that has already been printed.
+// Passing nil restores the default palette.
+func (s *Screen) SetPalette(p *Palette) {
+	if p == nil {
+		p = DefaultPalette()
+	}
+
+	*s.palette.Palette = *p
+	s.basePalette = *p
+	s.content.allDirty = true
+}
+
+// dynamicColorThemeNames are the theme colours used for the foreground, background and cursor when the palette has none.
+var dynamicColorThemeNames = []fyne.ThemeColorName{
+	theme.ColorNameForeground, theme.ColorNameBackground, theme.ColorNamePrimary,
+}
+
+// dynamicColor returns the foreground (0), background (1) or cursor (2) colour, as numbered by OSC 10 to 12.
+func (p *Palette) dynamicColor(which int) color.Color {
+	switch which {
+	case 0:
+		return p.Foreground
+	case 1:
+		return p.Background
+	default:
+		return p.Cursor
+	}
+}
+
+// parseColorSpec parses an X11 colour specification as used by OSC sequences.
+// It supports the forms "rgb:r/g/b" with 1 to 4 hex digits per channel and "#rgb" with 1 to 4 digits per channel.
+func parseColorSpec(spec string) (color.Color, bool) {
+	var parts []string
+	switch {
+	case strings.HasPrefix(spec, "rgb:"):
+		parts = strings.Split(spec[4:], "/")
+		if len(parts) != 3 {
+			return nil, false
+		}
+	case strings.HasPrefix(spec, "#") && len(spec) > 1 && (len(spec)-1)%3 == 0:
+		size := (len(spec) - 1) / 3
+		parts = []string{spec[1 : 1+size], spec[1+size : 1+2*size], spec[1+2*size:]}
+	default:
+		return nil, false
+	}
+
+	var rgb [3]uint16
+	for i, part := range parts {
+		if len(part) < 1 || len(part) > 4 {
+			return nil, false
+		}
+		v, err := strconv.ParseUint(part, 16, 16)
+		if err != nil {
+			return nil, false
+		}
+		// scale to 16 bits, so "f", "ff" and "ffff" are all full intensity
+		full := uint64(1)<<(4*uint(len(part))) - 1
+		rgb[i] = uint16(v * 0xffff / full)
+	}
+	return color.RGBA64{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xffff}, true
+}
+
+// formatColorSpec returns a colour in the "rgb:rrrr/gggg/bbbb" form used to answer OSC queries.
+// A nil colour is reported as the theme colour that is drawn in its place.
+func formatColorSpec(c color.Color, fallback fyne.ThemeColorName) string {
+	if c == nil {
+		c = theme.Color(fallback)
+	}
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("rgb:%04x/%04x/%04x", r, g, b)
+}
+
+// SetPalette changes the colours that the terminal draws with, including text that is already shown.
+// Passing nil restores the default palette.
+func (t *Terminal) SetPalette(p *Palette) {
+	t.screen.SetPalette(p)
+	t.Refresh()
+}
+
+// paletteColor returns a colour that refers to an entry in our palette, so it will change with the palette.
+func (s *Screen) paletteColor(index int) color.Color {
+	return widget2.PaletteColor{Palette: s.palette, Index: uint8(index)}
+}
+
+// screenPalette looks up the colours for the cells of a Screen.
+// The Palette it points to is updated in place so that cells keep referring to the current colours.
+type screenPalette struct {
+	*Palette
+}
+
+func (p screenPalette) IndexedColor(index uint8) color.Color {
+	if p.Palette == nil {
+		return nil
+	}
+	return p.Indexed[index]
+}
+
+func (p screenPalette) DefaultColors() (fg, bg color.Color) {
+	if p.Palette == nil {
+		return nil, nil
+	}
+	return p.Foreground, p.Background
+}
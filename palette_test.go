@@ -0,0 +1,61 @@
+package terminal
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPalette(t *testing.T) {
+	p := DefaultPalette()
+
+	assert.Nil(t, p.Foreground)
+	assert.Equal(t, &color.RGBA{0, 170, 170, 255}, p.Indexed[6])
+	assert.Equal(t, &color.RGBA{85, 255, 255, 255}, p.Indexed[14])
+	assert.Equal(t, &color.RGBA{0x5f, 0x87, 0xaf, 255}, p.Indexed[67])
+	assert.Equal(t, &color.Gray{230}, p.Indexed[255])
+}
+
+func TestScreen_SetPalette(t *testing.T) {
+	s := NewScreen(10, 1, nil)
+	_, _ = s.Write([]byte("\x1b[31;44ma\x1b[38;5;200mb\x1b[38;2;1;2;3mc\x1b[0md"))
+
+	p := DefaultPalette()
+	p.Indexed[1] = color.White
+	p.Indexed[4] = color.Black
+	p.Indexed[200] = color.White
+	p.Foreground = &color.RGBA{R: 1, A: 255}
+	s.SetPalette(p)
+
+	assert.Equal(t, color.White, s.Cell(0, 0).Style.TextColor())
+	assert.Equal(t, color.Black, s.Cell(0, 0).Style.BackgroundColor())
+	assert.Equal(t, color.White, s.Cell(0, 1).Style.TextColor())
+	assert.Equal(t, &color.RGBA{1, 2, 3, 255}, s.Cell(0, 2).Style.TextColor())
+	assert.Equal(t, p.Foreground, s.Cell(0, 3).Style.TextColor())
+
+	s.SetPalette(nil)
+	assert.Equal(t, &color.RGBA{170, 0, 0, 255}, s.Cell(0, 0).Style.TextColor())
+	assert.Nil(t, s.Cell(0, 3).Style.TextColor())
+}
+
+func TestTerminal_SetPalette(t *testing.T) {
+	test.NewApp()
+	term := New()
+	w := test.NewTempWindow(t, term)
+	w.Resize(fyne.NewSize(100, 50))
+	term.focused = true
+
+	p := DefaultPalette()
+	p.Background = color.White
+	p.Cursor = &color.RGBA{G: 255, A: 255}
+	p.Selection = &color.RGBA{B: 255, A: 255}
+	term.SetPalette(p)
+
+	assert.Equal(t, p.Cursor, term.cursor.FillColor)
+	assert.Equal(t, p.Selection, term.view.SelectionColor)
+	r := test.WidgetRenderer(term).(*render)
+	assert.Equal(t, color.White, r.background.FillColor)
+}
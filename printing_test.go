@@ -22,7 +22,7 @@ func TestHandleOutput_PrintMode(t *testing.T) {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			terminal := New()
-			terminal.handleOutput([]byte(test.inputSeq))
+			terminal.screen.handleOutput([]byte(test.inputSeq))
 		})
 	}
 }
@@ -80,15 +80,15 @@ func TestHandleOutput_Printing(t *testing.T) {
 			terminal := New()
 			terminal.Resize(fyne.NewSize(50, 50))
 			var spooledData []byte
-			terminal.printer = PrinterFunc(func(d []byte) {
+			terminal.screen.printer = PrinterFunc(func(d []byte) {
 				spooledData = d
 			})
-			terminal.handleOutput(test.inputSeq)
+			terminal.screen.handleOutput(test.inputSeq)
 
-			assert.Equal(t, test.expectedScreenData, terminal.content.Text())
-			assert.Equal(t, test.expectedPrintingState, terminal.state.printing)
+			assert.Equal(t, test.expectedScreenData, terminal.screen.content.Text())
+			assert.Equal(t, test.expectedPrintingState, terminal.screen.state.printing)
 			assert.Equal(t, test.expectedSpooledData, spooledData)
-			assert.Equal(t, test.expectedPrintData, terminal.printData)
+			assert.Equal(t, test.expectedPrintData, terminal.screen.printData)
 		})
 	}
 }
@@ -99,7 +99,7 @@ var examplePDFData []byte
 func TestHandleOutput_Printing_PDF(t *testing.T) {
 	terminal := New()
 	var spooledData []byte
-	terminal.printer = PrinterFunc(func(d []byte) {
+	terminal.screen.printer = PrinterFunc(func(d []byte) {
 		spooledData = d
 	})
 
@@ -113,7 +113,7 @@ func TestHandleOutput_Printing_PDF(t *testing.T) {
 			end = len(data)
 		}
 		t.Logf("sending chunk")
-		terminal.handleOutput(data[i:end])
+		terminal.screen.handleOutput(data[i:end])
 	}
 
 	assert.Equal(t, spooledData, examplePDFData)
@@ -0,0 +1,146 @@
+package terminal
+
+import "fyne.io/fyne/v2/widget"
+
+// reflow rewraps the main screen and its history to the current size.
+// Lines that were wrapped by the terminal are joined and split again, keeping the cursor
+// on the same character. If the alternate screen is active the saved main screen is reflowed.
+func (s *Screen) reflow() {
+	cols, rows := int(s.config.Columns), int(s.config.Rows)
+	if cols <= 0 || rows <= 0 {
+		return
+	}
+
+	screen, screenWrapped, curRow, curCol := s.content.Rows, s.content.wrapped, s.cursorRow, s.cursorCol
+	if s.altBufferActive {
+		screen, screenWrapped, curRow, curCol = s.altSavedGrid, s.altSavedWrapped, s.altSavedRow, s.altSavedCol
+	}
+
+	history := s.scrollback.len()
+	all := make([]widget.TextGridRow, 0, history+len(screen))
+	wrapped := make([]bool, 0, history+len(screen))
+	for i := 0; i < history; i++ {
+		all = append(all, s.scrollback.row(i))
+		wrapped = append(wrapped, s.scrollback.isWrapped(i))
+	}
+	all = append(all, screen...)
+	for i := range screen {
+		wrapped = append(wrapped, i < len(screenWrapped) && screenWrapped[i])
+	}
+	lines := s.commandLines()
+	marks := make([]int, len(lines))
+	for i, line := range lines {
+		marks[i] = *line - s.scrollback.firstLine()
+	}
+	all, wrapped, curRow, curCol = rewrap(all, wrapped, cols, history+curRow, curCol, marks)
+
+	// blank rows below the cursor will be recreated as output arrives
+	end := len(all)
+	for end > curRow+1 && len(all[end-1].Cells) == 0 {
+		end--
+	}
+	start := end - rows
+	if start > curRow {
+		start = curRow
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > start+rows {
+		end = start + rows
+	}
+
+	s.scrollback.clear()
+	for i, row := range all[:start] {
+		s.scrollback.push(row, wrapped[i])
+	}
+	if s.historyReset != nil {
+		s.historyReset()
+	}
+	for i, line := range lines {
+		*line = s.scrollback.nextLine() - start + marks[i]
+	}
+	screen = append([]widget.TextGridRow{}, all[start:end]...)
+	screenWrapped = append([]bool{}, wrapped[start:end]...)
+	curRow -= start
+
+	if s.altBufferActive {
+		s.altSavedGrid, s.altSavedWrapped, s.altSavedRow, s.altSavedCol = screen, screenWrapped, curRow, curCol
+		return
+	}
+	s.content.Rows, s.content.wrapped = screen, screenWrapped
+	s.content.allDirty = true
+	s.cursorRow, s.cursorCol = curRow, curCol
+}
+
+// rewrap joins soft wrapped rows into logical lines and splits them again at the given width.
+// Each entry of wrapped is true if the row at the same index continues on the next row,
+// the flags for the rows returned are returned in the same way.
+// The returned cursor position is on the same character as the cursor row and column passed in.
+// Each row index in marks is updated to the row that its first character moved to.
+func rewrap(rows []widget.TextGridRow, wrapped []bool, cols, curRow, curCol int, marks []int) (
+	[]widget.TextGridRow, []bool, int, int) {
+	for len(rows) <= curRow {
+		rows = append(rows, widget.TextGridRow{})
+	}
+
+	out := make([]widget.TextGridRow, 0, len(rows))
+	outWrapped := make([]bool, 0, len(rows))
+	moved := make([]int, len(rows))
+	newRow, newCol := curRow, curCol
+	for i := 0; i < len(rows); {
+		var line []widget.TextGridCell
+		cursorAt := -1
+		lineStart := i
+		for {
+			if i == curRow {
+				cursorAt = len(line) + curCol
+			}
+			moved[i] = len(line)
+			line = append(line, rows[i].Cells...)
+			more := i < len(wrapped) && wrapped[i]
+			i++
+			if !more || i >= len(rows) {
+				break
+			}
+		}
+
+		first := len(out)
+		for j := lineStart; j < i; j++ {
+			moved[j] = first + moved[j]/cols
+		}
+		if len(line) == 0 {
+			out = append(out, widget.TextGridRow{})
+			outWrapped = append(outWrapped, false)
+		}
+		for off := 0; off < len(line); off += cols {
+			end := off + cols
+			if end > len(line) {
+				end = len(line)
+			}
+			out = append(out, widget.TextGridRow{Cells: line[off:end:end]})
+			outWrapped = append(outWrapped, end < len(line))
+		}
+
+		if cursorAt < 0 {
+			continue
+		}
+		newRow, newCol = first+cursorAt/cols, cursorAt%cols
+		if newCol == 0 && cursorAt > 0 && cursorAt >= len(line) {
+			// the cursor was waiting to wrap after the last character, keep it at the end of the row
+			newRow--
+			newCol = cols
+		}
+		for len(out) <= newRow {
+			out = append(out, widget.TextGridRow{})
+			outWrapped = append(outWrapped, false)
+		}
+	}
+
+	for i, row := range marks {
+		if row >= 0 && row < len(moved) {
+			marks[i] = moved[row]
+		}
+	}
+	return out, outWrapped, newRow, newCol
+}
@@ -0,0 +1,111 @@
+package terminal
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReflow_SoftWrap(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 5
+	term.screen.config.Rows = 3
+	term.screen.scrollBottom = 2
+	term.Refresh() // ensure visuals set up
+
+	term.screen.handleOutput([]byte("HelloWorld\r\nHi"))
+	assert.Equal(t, "Hello\nWorld\nHi", term.screen.content.Text())
+	assert.True(t, term.screen.content.isWrapped(0))
+	assert.False(t, term.screen.content.isWrapped(1))
+	assert.False(t, term.screen.content.isWrapped(2))
+
+	// styling or moving the row does not change whether it wraps
+	term.screen.content.Rows[0].Style = &widget.CustomTextGridStyle{}
+	assert.True(t, term.screen.content.isWrapped(0))
+	term.screen.handleOutput([]byte("\x1b[H\x1b[L"))
+	assert.False(t, term.screen.content.isWrapped(2))
+	assert.True(t, term.screen.content.isWrapped(1))
+
+	term.screen.handleOutput([]byte("\x1b[?1049h\x1b[?1049l"))
+	assert.True(t, term.screen.content.isWrapped(1))
+}
+
+func TestReflow_Resize(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 5
+	term.screen.config.Rows = 3
+	term.screen.scrollBottom = 2
+	term.Refresh() // ensure visuals set up
+
+	term.screen.handleOutput([]byte("HelloWorld\r\nHi"))
+	term.screen.config.Columns = 10
+	term.screen.reflow()
+	assert.Equal(t, "HelloWorld\nHi", term.screen.content.Text())
+	assert.Equal(t, 1, term.screen.cursorRow)
+	assert.Equal(t, 2, term.screen.cursorCol)
+
+	term.screen.config.Columns = 4
+	term.screen.reflow()
+	assert.Equal(t, "Hell\noWor\nld\nHi", historyText(term)+"\n"+term.screen.content.Text())
+	assert.Equal(t, 1, term.screen.scrollback.len())
+	assert.Equal(t, 2, term.screen.cursorRow)
+	assert.Equal(t, 2, term.screen.cursorCol)
+
+	term.screen.config.Columns = 12
+	term.screen.reflow()
+	assert.Equal(t, 0, term.screen.scrollback.len())
+	assert.Equal(t, "HelloWorld\nHi", term.screen.content.Text())
+	assert.Equal(t, 1, term.screen.cursorRow)
+	assert.Equal(t, 2, term.screen.cursorCol)
+}
+
+func TestReflow_CursorInLine(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 4
+	term.screen.config.Rows = 3
+	term.screen.scrollBottom = 2
+	term.Refresh() // ensure visuals set up
+
+	term.screen.handleOutput([]byte("abcdefgh"))
+	assert.Equal(t, 1, term.screen.cursorRow)
+	assert.Equal(t, 4, term.screen.cursorCol) // waiting to wrap
+
+	term.screen.config.Columns = 8
+	term.screen.reflow()
+	assert.Equal(t, "abcdefgh", term.screen.content.Text())
+	assert.Equal(t, 0, term.screen.cursorRow)
+	assert.Equal(t, 8, term.screen.cursorCol)
+
+	term.screen.moveCursor(0, 5)
+	term.screen.config.Columns = 3
+	term.screen.reflow()
+	assert.Equal(t, "abc\ndef\ngh", term.screen.content.Text())
+	assert.Equal(t, 1, term.screen.cursorRow)
+	assert.Equal(t, 2, term.screen.cursorCol)
+}
+
+func TestRewrap_HardNewlines(t *testing.T) {
+	rows := []widget.TextGridRow{testRow("abc"), testRow(""), testRow("de")}
+	out, wrapped, row, col := rewrap(rows, nil, 2, 2, 1, nil)
+
+	assert.Equal(t, 4, len(out))
+	assert.Equal(t, []bool{true, false, false, false}, wrapped)
+	assert.Equal(t, "ab", rowText(out[0]))
+	assert.Equal(t, "c", rowText(out[1]))
+	assert.Equal(t, "", rowText(out[2]))
+	assert.Equal(t, "de", rowText(out[3]))
+	assert.Equal(t, 3, row)
+	assert.Equal(t, 1, col)
+}
+
+func historyText(t *Terminal) string {
+	text := ""
+	for i := 0; i < t.screen.scrollback.len(); i++ {
+		if i > 0 {
+			text += "\n"
+		}
+		text += rowText(t.screen.scrollback.row(i))
+	}
+	return text
+}
@@ -2,6 +2,7 @@ package terminal
 
 import (
 	"image/color"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -12,11 +13,13 @@ import (
 const cursorWidth = 2
 
 type render struct {
-	term *Terminal
+	term       *Terminal
+	background *canvas.Rectangle
 }
 
 func (r *render) Layout(s fyne.Size) {
-	r.term.content.Resize(s)
+	r.background.Resize(s)
+	r.term.view.Resize(s)
 }
 
 func (r *render) MinSize() fyne.Size {
@@ -29,7 +32,16 @@ func (r *render) Refresh() {
 		r.term.refreshCursor()
 	})
 
-	r.term.content.Refresh()
+	r.background.FillColor = r.term.screen.palette.Background
+	if r.background.FillColor == nil {
+		r.background.FillColor = color.Transparent
+	}
+	r.background.Refresh()
+
+	r.term.screen.content.takeDamage()
+	r.term.updateView()
+	r.term.view.SelectionColor = r.term.screen.palette.Selection
+	r.term.view.Refresh()
 }
 
 func (r *render) BackgroundColor() color.Color {
@@ -37,7 +49,7 @@ func (r *render) BackgroundColor() color.Color {
 }
 
 func (r *render) Objects() []fyne.CanvasObject {
-	return []fyne.CanvasObject{r.term.content, r.term.cursor}
+	return []fyne.CanvasObject{r.background, r.term.view, r.term.cursor}
 }
 
 func (r *render) Destroy() {
@@ -45,13 +57,15 @@ func (r *render) Destroy() {
 
 func (r *render) moveCursor() {
 	cell := r.term.guessCellSize()
-	r.term.cursor.Move(fyne.NewPos(cell.Width*float32(r.term.cursorCol), cell.Height*float32(r.term.cursorRow)))
+	r.term.cursor.Move(fyne.NewPos(cell.Width*float32(r.term.screen.cursorCol), cell.Height*float32(r.term.screen.cursorRow)))
 }
 
 func (t *Terminal) refreshCursor() {
-	t.cursor.Hidden = !t.focused || t.cursorHidden
+	t.cursor.Hidden = !t.focused || t.screen.cursorHidden || t.scrollOffset > 0
 	if t.bell {
 		t.cursor.FillColor = theme.Color(theme.ColorNameError)
+	} else if t.screen.palette.Cursor != nil {
+		t.cursor.FillColor = t.screen.palette.Cursor
 	} else {
 		t.cursor.FillColor = theme.Color(theme.ColorNamePrimary)
 	}
@@ -59,18 +73,50 @@ func (t *Terminal) refreshCursor() {
 	t.cursor.Refresh()
 }
 
+// refreshDamage redraws the rows that output has changed since the last refresh.
+// It falls back to a full Refresh if the view is scrolled back or the rows were added, replaced or resized.
+func (t *Terminal) refreshDamage() {
+	if t.view == nil || t.scrollOffset > 0 || len(t.view.Rows) != len(t.screen.content.Rows) {
+		t.Refresh()
+		return
+	}
+	rows, all := t.screen.content.takeDamage()
+	if all {
+		t.Refresh()
+		return
+	}
+
+	t.view.Rows = t.screen.content.Rows
+	t.view.RefreshRows(rows)
+	if t.screen.cursorMoved != nil {
+		t.screen.cursorMoved()
+	}
+	t.refreshCursor()
+}
+
+func (t *Terminal) ringBell() {
+	t.bell = true
+	t.Refresh()
+
+	go func() {
+		time.Sleep(time.Millisecond * 300)
+		t.bell = false
+		fyne.Do(t.Refresh)
+	}()
+}
+
 // CreateRenderer requests a new renderer for this terminal (just a wrapper around the TextGrid)
 func (t *Terminal) CreateRenderer() fyne.WidgetRenderer {
 	t.ExtendBaseWidget(t)
 
-	t.content = widget2.NewTermGrid()
+	t.view = widget2.NewTermGrid()
 	t.setupShortcuts()
 
 	t.cursor = canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
 	t.cursor.Hidden = true
 	t.cursor.Resize(fyne.NewSize(cursorWidth, t.guessCellSize().Height))
 
-	r := &render{term: t}
-	t.cursorMoved = r.moveCursor
+	r := &render{term: t, background: canvas.NewRectangle(color.Transparent)}
+	t.screen.cursorMoved = r.moveCursor
 	return r
 }
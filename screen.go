@@ -0,0 +1,374 @@
+package terminal
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"sort"
+	"sync"
+
+	"fyne.io/fyne/v2/widget"
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+)
+
+type charSet int
+
+const (
+	charSetANSII charSet = iota
+	charSetDECSpecialGraphics
+	charSetAlternate
+)
+
+type mouseMode int
+
+const (
+	mouseModeOff mouseMode = iota
+	mouseModeX10
+	mouseModeV200
+	mouseModeButton // also reports motion while a button is held
+	mouseModeAny    // also reports all motion
+)
+
+type mouseEncoding int
+
+const (
+	mouseEncodingDefault   mouseEncoding = iota
+	mouseEncodingSGR                     // CSI < b;x;y M or m, without a limit on the position
+	mouseEncodingURXVT                   // CSI b;x;y M
+	mouseEncodingSGRPixels               // as SGR, but the position is in pixels
+)
+
+// Screen is a headless terminal emulator.
+// It parses the output of an application and keeps the resulting grid of cells, cursor and modes
+// without needing a Fyne app or canvas. A Terminal widget displays a Screen, but it can also be used on its own,
+// for example to run an application in a server process or to check its output in tests.
+//
+// A Screen is not safe for concurrent use.
+type Screen struct {
+	content      *grid
+	config       Config
+	listenerLock sync.Mutex
+	listeners    []chan Config
+	reply        io.Writer // responses to queries from the application are written here
+
+	debug                   bool
+	currentFG, currentBG    color.Color
+	palette                 screenPalette      // the current colours, which applications can change
+	basePalette             Palette            // the colours that applications can reset to
+	style                   *widget2.CellStyle // shared by cells written with the current colours
+	cursorRow, cursorCol    int
+	savedRow, savedCol      int
+	scrollTop, scrollBottom int
+
+	// text attributes set by SGR that apply to new characters
+	bold, dim, italic, strikethrough bool
+	blinking, inverse, invisible     bool
+	underline                        widget2.UnderlineStyle
+	underlineColor                   color.Color        // nil to use the text colour
+	link                             *widget2.Hyperlink // set by OSC 8 for the text that follows
+
+	commands   []*shellCommand // the prompts and commands marked by OSC 133 shell integration
+	cellSize   image.Point     // the size in pixels of a cell, used to work out how many cells an image covers
+	kitty      *kittyGraphics  // images stored by the kitty graphics protocol, created when first used
+	imageFiles bool            // true if image data can be read from files, as the application runs on this computer
+
+	cursorHidden, bufferMode bool // buffer mode is an xterm extension that impacts control keys
+
+	// Alternate screen buffer (used by curses/fullscreen apps via ?1049h/?47h)
+	altSavedGrid    []widget.TextGridRow // saved main screen rows
+	altSavedWrapped []bool               // saved main screen soft wraps
+	altSavedRow     int                  // saved cursor row
+	altSavedCol     int                  // saved cursor col
+	altBufferActive bool                 // true when alternate buffer is in use
+
+	scrollback *scrollback // lines that scrolled off the top of the main screen
+
+	mouseMode     mouseMode
+	mouseEncoding mouseEncoding
+	g0Charset     charSet
+	g1Charset     charSet
+	useG1CharSet  bool
+
+	kittyKeyboard, kittyKeyboardAlt []int // the kitty keyboard flags pushed on the main and alternate screens
+	modifyOtherKeys                 int   // how keys typed with modifiers are sent, the xterm level from 0 to 2
+
+	newLineMode            bool // new line mode or line feed mode
+	bracketedPasteMode     bool
+	focusReporting         bool // send CSI I and CSI O when the terminal gains and loses focus (?1004)
+	disableAltEscape       bool // keys typed with Alt are not prefixed by ESC (?1036 and ?1039 off)
+	eightBitMeta           bool // keys typed with Alt have their eighth bit set if not prefixed by ESC (?1034)
+	disableAutoWrap        bool // disable auto wrap mode (DECAWM off)
+	disableAlternateScroll bool // the wheel does not send cursor keys in the alternate screen (?1007 off)
+	keypadApplication      bool // the numeric keypad sends SS3 sequences, set by DECKPAM (ESC =) or DECNKM (?66)
+	lastChar               rune // last graphic character output (for CSI b REP)
+	state                  *parseState
+	leftOver               []byte // the start of a character that was split across calls to Write
+	printData              []byte
+	printer                Printer
+
+	// hooks for a Terminal to act on the output, any of these may be nil
+	apcReceived      func(string)
+	bellRung         func()
+	clipboardRead    func(selection rune, reply func(content string)) // reply is only called if reading is allowed
+	clipboardWritten func(selection rune, content string)
+	commandFinished  func(Command)
+	cursorMoved      func()
+	dirChanged       func(string)
+	historyPushed    func() // a line scrolled off the top of the main screen into the history
+	historyReset     func() // the history was cleared or rebuilt, or hidden by the alternate screen
+	notified         func(title, body string)
+}
+
+// NewScreen creates a headless terminal screen with the given number of columns and rows.
+// Responses to queries from the application, such as device attributes, are written to reply.
+// If reply is nil the responses are discarded.
+func NewScreen(cols, rows int, reply io.Writer) *Screen {
+	if reply == nil {
+		reply = discardWriter{}
+	}
+	s := &Screen{
+		content:     &grid{},
+		palette:     screenPalette{DefaultPalette()},
+		basePalette: *DefaultPalette(),
+		reply:       reply,
+		scrollback:  newScrollback(defaultScrollbackLines),
+		cellSize:    image.Pt(defaultCellWidth, defaultCellHeight),
+	}
+	s.SetSize(cols, rows)
+
+	return s
+}
+
+// AddListener registers a new outgoing channel that will have our Config sent each time it changes.
+func (s *Screen) AddListener(listener chan Config) {
+	s.listenerLock.Lock()
+	defer s.listenerLock.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+// Cell returns the cell at the given row and column of the screen, counting from 0 at the top left.
+// Positions that have not been written to return an empty cell.
+func (s *Screen) Cell(row, col int) widget.TextGridCell {
+	cells := s.content.Row(row).Cells
+	if col < 0 || col >= len(cells) {
+		return widget.TextGridCell{}
+	}
+
+	return cells[col]
+}
+
+// CursorPosition returns the row and column of the cursor, counting from 0 at the top left.
+func (s *Screen) CursorPosition() (row, col int) {
+	return s.cursorRow, s.cursorCol
+}
+
+// CursorVisible returns false if the application has hidden the cursor.
+func (s *Screen) CursorVisible() bool {
+	return !s.cursorHidden
+}
+
+// Dimensions returns the number of columns and rows of the screen.
+func (s *Screen) Dimensions() (cols, rows int) {
+	return int(s.config.Columns), int(s.config.Rows)
+}
+
+// RemoveListener de-registers a Config channel and closes it
+func (s *Screen) RemoveListener(listener chan Config) {
+	s.listenerLock.Lock()
+	defer s.listenerLock.Unlock()
+
+	for i, l := range s.listeners {
+		if l == listener {
+			if i < len(s.listeners)-1 {
+				s.listeners = append(s.listeners[:i], s.listeners[i+1:]...)
+			} else {
+				s.listeners = s.listeners[:i]
+			}
+			close(l)
+			return
+		}
+	}
+}
+
+// SetDebug turns on output about terminal codes and other errors if the parameter is `true`.
+func (s *Screen) SetDebug(debug bool) {
+	s.debug = debug
+}
+
+// SetSize changes the number of columns and rows of the screen.
+// Lines that were wrapped because they were too long are wrapped again to fit the new width.
+func (s *Screen) SetSize(cols, rows int) {
+	oldRows, oldCols := int(s.config.Rows), int(s.config.Columns)
+	if cols == oldCols && rows == oldRows {
+		return
+	}
+
+	s.config.Columns, s.config.Rows = uint(cols), uint(rows)
+	s.content.columns = cols
+	if s.scrollBottom == 0 || s.scrollBottom == oldRows-1 {
+		s.scrollBottom = rows - 1
+	}
+	if oldCols > 0 {
+		s.reflow()
+	}
+	s.onConfigure()
+}
+
+// Text returns the contents of the buffer as a single string joined with `\n` (no style information).
+func (s *Screen) Text() string {
+	return s.content.Text()
+}
+
+// Write parses the output of an application and updates the screen.
+// A character that is split at the end of b is kept until the rest of it is written, so all of b is always consumed.
+func (s *Screen) Write(b []byte) (int, error) {
+	buf := b
+	if len(s.leftOver) > 0 {
+		buf = append(s.leftOver, b...)
+	}
+
+	s.leftOver = append([]byte(nil), s.handleOutput(buf)...)
+	return len(b), nil
+}
+
+func (s *Screen) onConfigure() {
+	s.listenerLock.Lock()
+	for _, l := range s.listeners {
+		select {
+		case l <- s.config:
+		default:
+			// channel blocked, might be closed
+		}
+	}
+	s.listenerLock.Unlock()
+}
+
+// grid holds the rows of a Screen.
+// It provides the parts of the widget.TextGrid API that we need without requiring a renderer.
+type grid struct {
+	Rows    []widget.TextGridRow
+	wrapped []bool // for each row, if it continues on the next row because autowrap moved the cursor
+
+	columns int // the capacity to reserve when a row grows, so it is not reallocated for each character
+
+	dirty    map[int]bool // rows changed since the damage was last taken
+	allDirty bool         // the rows were replaced, so everything needs to be redrawn
+}
+
+// markDirty records that the row given has changed and needs to be redrawn.
+func (g *grid) markDirty(row int) {
+	if g.dirty == nil {
+		g.dirty = make(map[int]bool)
+	}
+	g.dirty[row] = true
+}
+
+// markDirtyRange records that the rows from top to bottom, inclusive, have changed.
+func (g *grid) markDirtyRange(top, bottom int) {
+	for i := top; i <= bottom; i++ {
+		g.markDirty(i)
+	}
+}
+
+// takeDamage returns the rows that have changed since it was last called, in order, and resets the record.
+// If all is true the rows could not be tracked individually and the whole grid should be redrawn.
+func (g *grid) takeDamage() (rows []int, all bool) {
+	all = g.allDirty
+	if !all {
+		rows = make([]int, 0, len(g.dirty))
+		for row := range g.dirty {
+			rows = append(rows, row)
+		}
+		sort.Ints(rows)
+	}
+
+	g.allDirty = false
+	for row := range g.dirty {
+		delete(g.dirty, row)
+	}
+	return rows, all
+}
+
+// Row returns a copy of the content in a specified row, or an empty row if it is out of bounds.
+func (g *grid) Row(row int) widget.TextGridRow {
+	if row < 0 || row >= len(g.Rows) {
+		return widget.TextGridRow{}
+	}
+
+	return g.Rows[row]
+}
+
+// SetCell sets the cell at the row and column given, adding rows and cells as required.
+func (g *grid) SetCell(row, col int, cell widget.TextGridCell) {
+	if row < 0 || col < 0 {
+		return
+	}
+	for len(g.Rows) <= row {
+		g.Rows = append(g.Rows, widget.TextGridRow{})
+	}
+	if cells := g.Rows[row].Cells; len(cells) <= col {
+		if cap(cells) <= col {
+			size := g.columns
+			if size < 2*cap(cells) {
+				size = 2 * cap(cells)
+			}
+			if size <= col {
+				size = col + 1
+			}
+			grown := make([]widget.TextGridCell, len(cells), size)
+			copy(grown, cells)
+			cells = grown
+		}
+		// the cells past the end may be left over from a row that was shortened
+		for i := len(cells); i < col; i++ {
+			cells = append(cells, widget.TextGridCell{})
+		}
+		g.Rows[row].Cells = cells[:col+1]
+	}
+
+	g.Rows[row].Cells[col] = cell
+	g.markDirty(row)
+}
+
+// SetRow replaces the content of the row given, adding rows as required.
+func (g *grid) SetRow(row int, content widget.TextGridRow) {
+	if row < 0 {
+		return
+	}
+	for len(g.Rows) <= row {
+		g.Rows = append(g.Rows, widget.TextGridRow{})
+	}
+
+	g.Rows[row] = content
+	g.setWrapped(row, false)
+	g.markDirty(row)
+}
+
+// isWrapped returns true if the row given continues on the next row because autowrap moved the cursor,
+// rather than the line ending with a newline.
+func (g *grid) isWrapped(row int) bool {
+	return row >= 0 && row < len(g.wrapped) && g.wrapped[row]
+}
+
+// setWrapped records whether the row given continues on the next row.
+func (g *grid) setWrapped(row int, wrapped bool) {
+	if row < 0 || (!wrapped && row >= len(g.wrapped)) {
+		return
+	}
+	for len(g.wrapped) <= row {
+		g.wrapped = append(g.wrapped, false)
+	}
+	g.wrapped[row] = wrapped
+}
+
+// copyRow replaces the row dst with the content of row src, keeping whether it wraps.
+func (g *grid) copyRow(dst, src int) {
+	g.SetRow(dst, g.Row(src))
+	g.setWrapped(dst, g.isWrapped(src))
+}
+
+// Text returns the contents of the grid as a single string joined with `\n`.
+func (g *grid) Text() string {
+	return (&widget.TextGrid{Rows: g.Rows}).Text()
+}
@@ -0,0 +1,87 @@
+package terminal
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreen_Write(t *testing.T) {
+	s := NewScreen(10, 3, nil)
+	n, err := s.Write([]byte("Hello\r\n\x1b[31mWorld"))
+	assert.NoError(t, err)
+	assert.Equal(t, 17, n)
+
+	assert.Equal(t, "Hello\nWorld", s.Text())
+	row, col := s.CursorPosition()
+	assert.Equal(t, 1, row)
+	assert.Equal(t, 5, col)
+	assert.Equal(t, 'W', s.Cell(1, 0).Rune)
+	assert.Equal(t, basicColors[1], s.Cell(1, 0).Style.TextColor())
+	assert.Equal(t, rune(0), s.Cell(2, 0).Rune)
+
+	_, _ = s.Write([]byte("\x1b[?25l"))
+	assert.False(t, s.CursorVisible())
+}
+
+func TestScreen_WriteSplitRune(t *testing.T) {
+	s := NewScreen(10, 1, nil)
+	euro := []byte("€")
+	_, _ = s.Write(euro[:1])
+	assert.Equal(t, "", s.Text())
+
+	_, _ = s.Write(euro[1:])
+	assert.Equal(t, "€", s.Text())
+}
+
+func TestScreen_Reply(t *testing.T) {
+	reply := &bytes.Buffer{}
+	s := NewScreen(10, 1, reply)
+	_, _ = s.Write([]byte("\x1b[c"))
+
+	assert.Equal(t, "\x1b[?2;4;22c", reply.String())
+}
+
+func TestScreen_SetSize(t *testing.T) {
+	s := NewScreen(5, 2, nil)
+	listen := make(chan Config, 1)
+	s.AddListener(listen)
+	_, _ = s.Write([]byte("HelloWorld"))
+
+	s.SetSize(10, 2)
+	assert.Equal(t, "HelloWorld", s.Text())
+	cols, rows := s.Dimensions()
+	assert.Equal(t, 10, cols)
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, uint(10), (<-listen).Columns)
+}
+
+func TestScreen_WriteAfterErase(t *testing.T) {
+	s := NewScreen(10, 1, nil)
+	_, _ = s.Write([]byte("Hello\r\x1b[K\x1b[1;4Hx"))
+
+	assert.Equal(t, "   x", s.Text())
+}
+
+func TestScreen_Damage(t *testing.T) {
+	s := NewScreen(10, 3, nil)
+	_, _ = s.Write([]byte("a\r\nb\r\nc"))
+	s.content.takeDamage()
+
+	_, _ = s.Write([]byte("\x1b[2;1Hx"))
+	rows, all := s.content.takeDamage()
+	assert.Equal(t, []int{1}, rows)
+	assert.False(t, all)
+
+	_, _ = s.Write([]byte("\x1b[3;1H\n"))
+	rows, _ = s.content.takeDamage()
+	assert.Equal(t, []int{0, 1, 2}, rows)
+
+	s.SetSize(5, 3)
+	_, all = s.content.takeDamage()
+	assert.True(t, all)
+	rows, all = s.content.takeDamage()
+	assert.Empty(t, rows)
+	assert.False(t, all)
+}
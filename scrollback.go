@@ -0,0 +1,225 @@
+package terminal
+
+import (
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+const defaultScrollbackLines = 1000
+
+// scrollback is a bounded ring of the rows that have scrolled off the top of the screen.
+// The oldest rows are discarded once the limit is reached.
+type scrollback struct {
+	rows    []widget.TextGridRow
+	wrapped []bool // for each row, if it continues on the next row because autowrap moved the cursor
+	start   int    // index of the oldest row once the ring is full
+	limit   int
+	pushed  int // count of all rows ever pushed, so lines can be numbered as they move into history
+}
+
+func newScrollback(limit int) *scrollback {
+	return &scrollback{limit: limit}
+}
+
+func (s *scrollback) len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.rows)
+}
+
+// push adds a row as the newest line of history, dropping the oldest if we are full.
+// If wrapped is true the row continues on the row pushed after it.
+func (s *scrollback) push(row widget.TextGridRow, wrapped bool) {
+	if s == nil {
+		return
+	}
+	s.pushed++
+	if s.limit <= 0 {
+		return
+	}
+
+	if len(s.rows) < s.limit {
+		s.rows = append(s.rows, row)
+		s.wrapped = append(s.wrapped, wrapped)
+		return
+	}
+	s.rows[s.start] = row
+	s.wrapped[s.start] = wrapped
+	s.start = (s.start + 1) % len(s.rows)
+}
+
+// firstLine returns the line number of the oldest row kept, lines are numbered from the first row ever pushed.
+func (s *scrollback) firstLine() int {
+	if s == nil {
+		return 0
+	}
+	return s.pushed - len(s.rows)
+}
+
+// nextLine returns the line number that the next row pushed will have, which is the line of the top of the screen.
+func (s *scrollback) nextLine() int {
+	if s == nil {
+		return 0
+	}
+	return s.pushed
+}
+
+// row returns the history line at index i, where 0 is the oldest line kept.
+func (s *scrollback) row(i int) widget.TextGridRow {
+	if i < 0 || i >= s.len() {
+		return widget.TextGridRow{}
+	}
+	return s.rows[(s.start+i)%len(s.rows)]
+}
+
+// isWrapped returns true if the history line at index i continues on the next line because of autowrap.
+func (s *scrollback) isWrapped(i int) bool {
+	if i < 0 || i >= s.len() {
+		return false
+	}
+	return s.wrapped[(s.start+i)%len(s.rows)]
+}
+
+// setLimit changes the number of lines kept, discarding the oldest lines if necessary.
+func (s *scrollback) setLimit(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	keep := s.len()
+	if keep > limit {
+		keep = limit
+	}
+
+	rows := make([]widget.TextGridRow, keep)
+	wrapped := make([]bool, keep)
+	for i := range rows {
+		rows[i] = s.row(s.len() - keep + i)
+		wrapped[i] = s.isWrapped(s.len() - keep + i)
+	}
+	s.rows, s.wrapped = rows, wrapped
+	s.start = 0
+	s.limit = limit
+}
+
+func (s *scrollback) clear() {
+	if s == nil {
+		return
+	}
+	s.rows, s.wrapped = nil, nil
+	s.start = 0
+}
+
+// SetScrollbackLines sets how many lines of history are kept after they scroll off the top of the screen.
+// Setting 0 disables the scrollback, any history beyond the new limit is discarded.
+func (s *Screen) SetScrollbackLines(lines int) {
+	if s.scrollback == nil {
+		s.scrollback = newScrollback(lines)
+		return
+	}
+
+	s.scrollback.setLimit(lines)
+}
+
+// SetScrollbackLines sets how many lines of history are kept after they scroll off the top of the screen.
+// Setting 0 disables the scrollback, any history beyond the new limit is discarded.
+func (t *Terminal) SetScrollbackLines(lines int) {
+	t.screen.SetScrollbackLines(lines)
+	if t.scrollOffset > t.screen.scrollback.len() {
+		t.scrollOffset = t.screen.scrollback.len()
+	}
+}
+
+// Scrolled is called when the user scrolls over the terminal, allowing them to view the history.
+// If the application asked for mouse events the wheel is reported to it instead, and in the alternate screen
+// the wheel sends cursor up and down keys, so that pagers and editors scroll.
+func (t *Terminal) Scrolled(ev *fyne.ScrollEvent) {
+	cell := t.guessCellSize()
+	lines := int(math.Round(float64(ev.Scrolled.DY / cell.Height)))
+	if lines == 0 {
+		if ev.Scrolled.DY > 0 {
+			lines = 1
+		} else if ev.Scrolled.DY < 0 {
+			lines = -1
+		}
+	}
+
+	switch {
+	case t.screen.mouseMode != mouseModeOff:
+		t.mouseWheel(lines, ev.Position)
+	case t.screen.altBufferActive:
+		if t.screen.disableAlternateScroll || t.in == nil {
+			return
+		}
+		key := fyne.KeyUp
+		if lines < 0 {
+			key, lines = fyne.KeyDown, -lines
+		}
+		for ; lines > 0; lines-- {
+			t.typeCursorKey(key)
+		}
+	default:
+		t.scrollHistory(lines)
+	}
+}
+
+// scrollHistory moves the view back (positive) or forward (negative) through the history by the given lines.
+func (t *Terminal) scrollHistory(lines int) {
+	offset := t.scrollOffset + lines
+	if offset > t.screen.scrollback.len() {
+		offset = t.screen.scrollback.len()
+	} else if offset < 0 {
+		offset = 0
+	}
+	if offset == t.scrollOffset {
+		return
+	}
+
+	if t.hasSelectedText() {
+		t.clearSelectedText()
+	}
+	t.scrollOffset = offset
+	t.Refresh()
+}
+
+// historyPushed keeps a view of the history on the same lines when another line scrolls into it.
+func (t *Terminal) historyPushed() {
+	if t.scrollOffset > 0 && t.scrollOffset < t.screen.scrollback.len() {
+		t.scrollOffset++
+	}
+}
+
+// historyReset returns the view to the live screen when the history it showed has gone.
+func (t *Terminal) historyReset() {
+	t.scrollOffset = 0
+}
+
+// scrollToBottom returns the view to the live screen if the user was looking through the history.
+func (t *Terminal) scrollToBottom() {
+	if t.scrollOffset == 0 {
+		return
+	}
+
+	t.scrollHistory(-t.scrollOffset)
+}
+
+// updateView fills the view with the rows visible at the current scroll offset.
+func (t *Terminal) updateView() {
+	if t.scrollOffset == 0 {
+		t.view.Rows = t.screen.content.Rows
+		return
+	}
+
+	rows := int(t.screen.config.Rows)
+	view := make([]widget.TextGridRow, 0, rows)
+	for i := t.screen.scrollback.len() - t.scrollOffset; i < t.screen.scrollback.len() && len(view) < rows; i++ {
+		view = append(view, t.screen.scrollback.row(i))
+	}
+	for i := 0; len(view) < rows && i < len(t.screen.content.Rows); i++ {
+		view = append(view, t.screen.content.Rows[i])
+	}
+
+	t.view.Rows = view
+}
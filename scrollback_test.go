@@ -0,0 +1,136 @@
+package terminal
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrollback_Ring(t *testing.T) {
+	s := newScrollback(3)
+	for i := 1; i <= 5; i++ {
+		s.push(testRow("Line "+strconv.Itoa(i)), false)
+	}
+
+	assert.Equal(t, 3, s.len())
+	assert.Equal(t, "Line 3", rowText(s.row(0)))
+	assert.Equal(t, "Line 5", rowText(s.row(2)))
+
+	s.setLimit(2)
+	assert.Equal(t, 2, s.len())
+	assert.Equal(t, "Line 4", rowText(s.row(0)))
+	assert.Equal(t, "Line 5", rowText(s.row(1)))
+
+	s.push(testRow("Line 6"), false)
+	assert.Equal(t, "Line 5", rowText(s.row(0)))
+	assert.Equal(t, "Line 6", rowText(s.row(1)))
+
+	s.setLimit(0)
+	s.push(testRow("Line 7"), false)
+	assert.Equal(t, 0, s.len())
+}
+
+func TestScrollback_ScrollDown(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 10
+	term.screen.config.Rows = 2
+	term.screen.scrollBottom = 1
+	term.Refresh() // ensure visuals set up
+
+	term.screen.handleOutput([]byte("Line 1\r\nLine 2\r\nLine 3\r\nLine 4"))
+	assert.Equal(t, "Line 3\nLine 4", term.screen.content.Text())
+	assert.Equal(t, 2, term.screen.scrollback.len())
+	assert.Equal(t, "Line 1", rowText(term.screen.scrollback.row(0)))
+	assert.Equal(t, "Line 2", rowText(term.screen.scrollback.row(1)))
+
+	term.scrollHistory(1)
+	assert.Equal(t, "Line 2\nLine 3", term.view.Text())
+	term.scrollHistory(5)
+	assert.Equal(t, 2, term.scrollOffset)
+	assert.Equal(t, "Line 1\nLine 2", term.view.Text())
+
+	term.screen.handleOutput([]byte("\r\nLine 5"))
+	assert.Equal(t, "Line 1\nLine 2", term.view.Text())
+
+	term.TypedRune('a')
+	assert.Equal(t, 0, term.scrollOffset)
+	assert.Equal(t, "Line 4\nLine 5", term.view.Text())
+
+	term.scrollHistory(1)
+	term.screen.handleEscape("3J")
+	assert.Equal(t, 0, term.screen.scrollback.len())
+	assert.Equal(t, 0, term.scrollOffset)
+}
+
+func TestScrollback_AltBuffer(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 10
+	term.screen.config.Rows = 2
+	term.screen.scrollBottom = 1
+	term.Refresh() // ensure visuals set up
+
+	term.screen.handleOutput([]byte("\x1b[?1049h"))
+	term.screen.handleOutput([]byte("Line 1\r\nLine 2\r\nLine 3"))
+	assert.Equal(t, 0, term.screen.scrollback.len())
+
+	term.Scrolled(&fyne.ScrollEvent{Scrolled: fyne.Delta{DY: 10}})
+	assert.Equal(t, 0, term.scrollOffset)
+}
+
+func TestScrollback_SetScrollbackLines(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 10
+	term.screen.config.Rows = 1
+	term.Refresh() // ensure visuals set up
+
+	term.SetScrollbackLines(2)
+	term.screen.handleOutput([]byte("Line 1\r\nLine 2\r\nLine 3\r\nLine 4"))
+	assert.Equal(t, 2, term.screen.scrollback.len())
+
+	term.SetScrollbackLines(0)
+	assert.Equal(t, 0, term.screen.scrollback.len())
+	term.screen.handleOutput([]byte("\r\nLine 5"))
+	assert.Equal(t, 0, term.screen.scrollback.len())
+}
+
+func rowText(row widget.TextGridRow) string {
+	runes := make([]rune, len(row.Cells))
+	for i, c := range row.Cells {
+		runes[i] = c.Rune
+	}
+	return string(runes)
+}
+
+func testRow(text string) widget.TextGridRow {
+	var row widget.TextGridRow
+	for _, r := range text {
+		row.Cells = append(row.Cells, widget.TextGridCell{Rune: r})
+	}
+	return row
+}
+
+func TestScrollback_AlternateScroll(t *testing.T) {
+	reply := &bytes.Buffer{}
+	term := New()
+	term.in = NopCloser(reply)
+	term.screen.config.Columns = 10
+	term.screen.config.Rows = 2
+	term.screen.scrollBottom = 1
+
+	term.screen.handleOutput([]byte("\x1b[?1049h"))
+	term.Scrolled(&fyne.ScrollEvent{Scrolled: fyne.Delta{DY: 1}})
+	assert.Equal(t, "\x1bOA", reply.String())
+	reply.Reset()
+	term.Scrolled(&fyne.ScrollEvent{Scrolled: fyne.Delta{DY: -2 * term.guessCellSize().Height}})
+	assert.Equal(t, "\x1bOB\x1bOB", reply.String())
+
+	reply.Reset()
+	term.screen.handleOutput([]byte("\x1b[?1007l"))
+	term.Scrolled(&fyne.ScrollEvent{Scrolled: fyne.Delta{DY: 1}})
+	assert.Equal(t, "", reply.String())
+	assert.Equal(t, 0, term.scrollOffset)
+}
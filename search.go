@@ -0,0 +1,260 @@
+package terminal
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+)
+
+// SearchOptions configures how Terminal.Find matches text.
+type SearchOptions struct {
+	// CaseSensitive requires the letter case of a match to be the same as the query.
+	CaseSensitive bool
+	// Regexp treats the query as a regular expression, using the syntax of the regexp package.
+	Regexp bool
+}
+
+// SearchMatch is the location of some text found by Terminal.Find.
+// Rows count lines from the oldest line of history, followed by the rows of the screen.
+// Row and Col are the first cell of the match and EndRow and EndCol are the last.
+// A match continues over the following rows if autowrap split the line that it is on.
+type SearchMatch struct {
+	Row, Col, EndRow, EndCol int
+}
+
+type searchState struct {
+	matches []SearchMatch
+	current int
+	first   int // the line number of history row 0 when the search ran
+}
+
+// Find searches the history and screen for the query and highlights all of the matches.
+// The matches are returned in order from the top of the history. The last match becomes the current match
+// and is scrolled into view, use FindPrevious and FindNext to move through them.
+// An error is returned if the options request a regular expression and the query cannot be parsed.
+func (t *Terminal) Find(query string, opts SearchOptions) ([]SearchMatch, error) {
+	t.clearSearch()
+	if query == "" {
+		t.Refresh()
+		return nil, nil
+	}
+
+	match, err := searchMatcher(query, opts)
+	if err != nil {
+		t.Refresh()
+		return nil, err
+	}
+
+	grid := t.searchGrid()
+	var found []SearchMatch
+	for i := 0; i < len(grid.Rows); {
+		// join the rows that autowrap split, remembering where each one starts in the line
+		var line []rune
+		var starts []int
+		first := i
+		for {
+			starts = append(starts, len(line))
+			line = append(line, rowRunes(grid.Rows[i])...)
+			i++
+			if !t.searchWrapped(i-1) || i >= len(grid.Rows) {
+				break
+			}
+		}
+
+		for _, m := range match(line) {
+			row, col := lineCell(starts, m[0])
+			endRow, endCol := lineCell(starts, m[1]-1)
+			found = append(found, SearchMatch{Row: first + row, Col: col, EndRow: first + endRow, EndCol: endCol})
+		}
+	}
+	if len(found) == 0 {
+		t.Refresh()
+		return nil, nil
+	}
+
+	t.search = &searchState{matches: found, current: len(found) - 1, first: t.screen.scrollback.firstLine()}
+	for i := range found {
+		t.highlightMatch(grid, i)
+	}
+	t.showMatch(found[len(found)-1])
+	return found, nil
+}
+
+// FindNext moves to the match after the current one, wrapping around after the last match,
+// and scrolls it into view. It returns false if there are no matches from the last call to Find.
+func (t *Terminal) FindNext() (SearchMatch, bool) {
+	return t.moveSearch(1)
+}
+
+// FindPrevious moves to the match before the current one, wrapping around before the first match,
+// and scrolls it into view. It returns false if there are no matches from the last call to Find.
+func (t *Terminal) FindPrevious() (SearchMatch, bool) {
+	return t.moveSearch(-1)
+}
+
+// ClearSearch removes the highlighting of matches found by Find.
+func (t *Terminal) ClearSearch() {
+	t.clearSearch()
+	t.Refresh()
+}
+
+func (t *Terminal) clearSearch() {
+	if t.search == nil || t.screen.content == nil {
+		return
+	}
+
+	widget2.ClearSearchHighlight(t.searchGrid())
+	t.search = nil
+}
+
+func (t *Terminal) highlightMatch(grid *widget2.TermGrid, i int) {
+	m := t.searchRow(t.search.matches[i])
+	if m.Row < 0 {
+		return
+	}
+	fg, bg := theme.Color(theme.ColorNameForegroundOnWarning), theme.Color(theme.ColorNameWarning)
+	if i == t.search.current {
+		fg, bg = theme.Color(theme.ColorNameForegroundOnPrimary), theme.Color(theme.ColorNamePrimary)
+	}
+
+	widget2.HighlightSearchRange(grid, m.Row, m.Col, m.EndRow, m.EndCol, highlightBitMask, fg, bg)
+}
+
+func (t *Terminal) moveSearch(dir int) (SearchMatch, bool) {
+	if t.search == nil || len(t.search.matches) == 0 {
+		return SearchMatch{}, false
+	}
+
+	grid := t.searchGrid()
+	last := t.search.current
+	t.search.current = (last + dir + len(t.search.matches)) % len(t.search.matches)
+	t.highlightMatch(grid, last)
+	t.highlightMatch(grid, t.search.current)
+
+	m := t.searchRow(t.search.matches[t.search.current])
+	t.showMatch(m)
+	return m, true
+}
+
+// searchGrid returns a grid containing the history followed by the screen.
+// The cells are shared with the terminal so styles applied here are displayed.
+func (t *Terminal) searchGrid() *widget2.TermGrid {
+	history := t.screen.scrollback.len()
+	rows := make([]widget.TextGridRow, 0, history+len(t.screen.content.Rows))
+	for i := 0; i < history; i++ {
+		rows = append(rows, t.screen.scrollback.row(i))
+	}
+
+	return &widget2.TermGrid{TextGrid: widget.TextGrid{Rows: append(rows, t.screen.content.Rows...)}}
+}
+
+// searchRow updates the row of a match to account for lines that have moved into history since the search.
+func (t *Terminal) searchRow(m SearchMatch) SearchMatch {
+	moved := t.search.first - t.screen.scrollback.firstLine()
+	m.Row += moved
+	m.EndRow += moved
+	return m
+}
+
+// searchWrapped returns true if the row of the search grid continues on the next row because of autowrap.
+func (t *Terminal) searchWrapped(row int) bool {
+	history := t.screen.scrollback.len()
+	if row < history {
+		return t.screen.scrollback.isWrapped(row)
+	}
+	return t.screen.content.isWrapped(row - history)
+}
+
+// showMatch scrolls the view so that the row of the match is visible.
+func (t *Terminal) showMatch(m SearchMatch) {
+	history := t.screen.scrollback.len()
+	top := history - t.scrollOffset
+	bottom := top + int(t.screen.config.Rows) - 1
+	switch {
+	case m.Row < 0:
+		// the line has been dropped from the history
+	case m.Row < top:
+		t.scrollOffset = history - m.Row
+	case m.Row > bottom:
+		t.scrollOffset -= m.Row - bottom
+		if t.scrollOffset < 0 {
+			t.scrollOffset = 0
+		}
+	}
+	t.Refresh()
+}
+
+// lineCell returns the row and column of an offset in a line joined from rows that begin at the offsets in starts.
+func lineCell(starts []int, off int) (int, int) {
+	row := 0
+	for row+1 < len(starts) && starts[row+1] <= off {
+		row++
+	}
+	return row, off - starts[row]
+}
+
+func rowRunes(row widget.TextGridRow) []rune {
+	runes := make([]rune, len(row.Cells))
+	for i, c := range row.Cells {
+		if c.Rune == 0 {
+			runes[i] = ' '
+		} else {
+			runes[i] = c.Rune
+		}
+	}
+	return runes
+}
+
+// searchMatcher returns a function that returns the start and end (exclusive) of matches in a line.
+func searchMatcher(query string, opts SearchOptions) (func([]rune) [][2]int, error) {
+	if opts.Regexp {
+		if !opts.CaseSensitive {
+			query = "(?i)" + query
+		}
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(line []rune) [][2]int {
+			text := string(line)
+			var found [][2]int
+			for _, loc := range re.FindAllStringIndex(text, -1) {
+				if loc[0] == loc[1] {
+					continue // an empty match cannot be highlighted
+				}
+				start := utf8.RuneCountInString(text[:loc[0]])
+				found = append(found, [2]int{start, start + utf8.RuneCountInString(text[loc[0]:loc[1]])})
+			}
+			return found
+		}, nil
+	}
+
+	needle := []rune(query)
+	return func(line []rune) [][2]int {
+		var found [][2]int
+		for i := 0; i+len(needle) <= len(line); i++ {
+			if runesMatch(line[i:i+len(needle)], needle, opts.CaseSensitive) {
+				found = append(found, [2]int{i, i + len(needle)})
+				i += len(needle) - 1
+			}
+		}
+		return found
+	}, nil
+}
+
+func runesMatch(a, b []rune, caseSensitive bool) bool {
+	for i := range a {
+		if a[i] == b[i] {
+			continue
+		}
+		if caseSensitive || unicode.ToLower(a[i]) != unicode.ToLower(b[i]) {
+			return false
+		}
+	}
+	return true
+}
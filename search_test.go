@@ -0,0 +1,105 @@
+package terminal
+
+import (
+	"testing"
+
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 20
+	term.screen.config.Rows = 2
+	term.screen.scrollBottom = 1
+	term.Refresh() // ensure visuals set up
+
+	term.screen.handleOutput([]byte("error: one\r\nok\r\nError: two\r\nwarning"))
+
+	found, err := term.Find("error", SearchOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchMatch{{Row: 0, Col: 0, EndCol: 4}, {Row: 2, Col: 0, EndRow: 2, EndCol: 4}}, found)
+	assert.Equal(t, 0, term.scrollOffset) // last match is on screen
+
+	style := term.screen.content.Rows[0].Cells[0].Style.(*widget2.TermTextGridStyle)
+	assert.NotNil(t, style.SearchBackgroundColor)
+
+	found, _ = term.Find("error", SearchOptions{CaseSensitive: true})
+	assert.Equal(t, []SearchMatch{{Row: 0, Col: 0, EndCol: 4}}, found)
+	assert.Equal(t, 2, term.scrollOffset)
+	assert.Nil(t, style.SearchBackgroundColor)
+}
+
+func TestFind_Regexp(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 20
+	term.screen.config.Rows = 2
+	term.screen.scrollBottom = 1
+	term.Refresh() // ensure visuals set up
+
+	term.screen.handleOutput([]byte("main.go:12: bad\r\nutil.go:3: worse"))
+
+	found, err := term.Find(`\w+\.go:\d+`, SearchOptions{Regexp: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchMatch{{Row: 0, Col: 0, EndCol: 9}, {Row: 1, Col: 0, EndRow: 1, EndCol: 8}}, found)
+
+	_, err = term.Find("(", SearchOptions{Regexp: true})
+	assert.Error(t, err)
+}
+
+func TestFind_Wrapped(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 5
+	term.screen.config.Rows = 4
+	term.screen.scrollBottom = 3
+	term.Refresh() // ensure visuals set up
+
+	term.screen.handleOutput([]byte("an error\r\nerr\r\nor"))
+
+	found, err := term.Find("error", SearchOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchMatch{{Row: 0, Col: 3, EndRow: 1, EndCol: 2}}, found)
+
+	for _, cell := range []int{3, 4} {
+		style := term.screen.content.Rows[0].Cells[cell].Style.(*widget2.TermTextGridStyle)
+		assert.NotNil(t, style.SearchBackgroundColor)
+	}
+	style := term.screen.content.Rows[1].Cells[2].Style.(*widget2.TermTextGridStyle)
+	assert.NotNil(t, style.SearchBackgroundColor)
+	style, _ = term.screen.content.Rows[2].Cells[2].Style.(*widget2.TermTextGridStyle)
+	assert.True(t, style == nil || style.SearchBackgroundColor == nil)
+}
+
+func TestFind_Navigate(t *testing.T) {
+	term := New()
+	term.screen.config.Columns = 20
+	term.screen.config.Rows = 1
+	term.Refresh() // ensure visuals set up
+
+	_, ok := term.FindNext()
+	assert.False(t, ok)
+
+	term.screen.handleOutput([]byte("a1\r\na2\r\na3"))
+	found, _ := term.Find("a", SearchOptions{})
+	assert.Equal(t, 3, len(found))
+
+	m, ok := term.FindPrevious()
+	assert.True(t, ok)
+	assert.Equal(t, SearchMatch{Row: 1, Col: 0, EndRow: 1, EndCol: 0}, m)
+	assert.Equal(t, 1, term.scrollOffset)
+
+	m, _ = term.FindPrevious()
+	assert.Equal(t, 0, m.Row)
+	assert.Equal(t, 2, term.scrollOffset)
+
+	m, _ = term.FindPrevious()
+	assert.Equal(t, 2, m.Row)
+	assert.Equal(t, 0, term.scrollOffset)
+
+	m, _ = term.FindNext()
+	assert.Equal(t, 0, m.Row)
+
+	term.ClearSearch()
+	_, ok = term.FindNext()
+	assert.False(t, ok)
+}
@@ -42,13 +42,13 @@ func (t *Terminal) getSelectedRange() (int, int, int, int) {
 
 func (t *Terminal) highlightSelectedText() {
 	sr, sc, er, ec := t.getSelectedRange()
-	widget2.HighlightRange(t.content, t.blockMode, sr, sc, er, ec, highlightBitMask)
+	widget2.HighlightRange(t.view, t.blockMode, sr, sc, er, ec, highlightBitMask)
 	t.Refresh()
 }
 
 func (t *Terminal) clearSelectedText() {
 	sr, sc, er, ec := t.getSelectedRange()
-	widget2.ClearHighlightRange(t.content, t.blockMode, sr, sc, er, ec)
+	widget2.ClearHighlightRange(t.view, t.blockMode, sr, sc, er, ec)
 	t.Refresh()
 	t.blockMode = false
 	t.selecting = false
@@ -59,7 +59,7 @@ func (t *Terminal) clearSelectedText() {
 // SelectedText gets the text that is currently selected.
 func (t *Terminal) SelectedText() string {
 	sr, sc, er, ec := t.getSelectedRange()
-	return widget2.GetTextRange(t.content, t.blockMode, sr, sc, er, ec)
+	return widget2.GetTextRange(t.view, t.blockMode, sr, sc, er, ec)
 }
 
 func (t *Terminal) copySelectedText(clipboard fyne.Clipboard) {
@@ -72,7 +72,7 @@ func (t *Terminal) copySelectedText(clipboard fyne.Clipboard) {
 func (t *Terminal) pasteText(clipboard fyne.Clipboard) {
 	content := clipboard.Content()
 
-	if t.bracketedPasteMode {
+	if t.screen.bracketedPasteMode {
 		_, _ = t.in.Write(append(
 			append(
 				[]byte{asciiEscape, '[', '2', '0', '0', '~'},
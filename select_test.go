@@ -127,7 +127,8 @@ func TestDoubleTapped(t *testing.T) {
 	}
 
 	term := &Terminal{
-		content: grid,
+		screen: NewScreen(0, 0, nil),
+		view:   grid,
 	}
 	term.Resize(fyne.NewSize(500, 500))
 
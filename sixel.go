@@ -0,0 +1,293 @@
+package terminal
+
+import (
+	"image"
+	"image/color"
+	"log"
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2/theme"
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+)
+
+const maxSixelSize = 10000 // the largest width or height of a sixel image, larger images are clipped
+
+// sixelColors are the colour registers of a VT340, used until an image defines its own.
+var sixelColors = [16]color.NRGBA{
+	{0, 0, 0, 255}, {51, 51, 204, 255}, {204, 36, 36, 255}, {51, 204, 51, 255},
+	{204, 51, 204, 255}, {51, 204, 204, 255}, {204, 204, 51, 255}, {120, 120, 120, 255},
+	{69, 69, 69, 255}, {87, 87, 153, 255}, {153, 69, 69, 255}, {87, 153, 87, 255},
+	{153, 87, 153, 255}, {87, 153, 153, 255}, {153, 153, 87, 255}, {204, 204, 204, 255},
+}
+
+// sixelDecoder draws sixel data into an image that grows as it is drawn.
+// Each sixel is a column of six pixels, written left to right in bands that are six pixels tall.
+type sixelDecoder struct {
+	img           *image.NRGBA
+	colors        [256]color.NRGBA
+	current       color.NRGBA
+	x, y          int // the position of the next sixel, y is the top of the current band
+	width, height int // the size of the image, from the raster attributes or the pixels drawn
+}
+
+// handleSixel decodes a sixel image from the parameters and data of a DCS sequence and places it at the cursor.
+// The cursor moves to the row below the image. Pixels that are not drawn are filled with the background colour,
+// unless the second parameter is 1, when they are left transparent so the cells beneath show through.
+func (s *Screen) handleSixel(params, data string) {
+	var background color.Color
+	if p := strings.Split(params, ";"); len(p) < 2 || p[1] != "1" {
+		background = s.sixelBackground()
+	}
+	img := decodeSixel(data, background)
+	if img == nil {
+		if s.debug {
+			log.Println("Empty sixel image")
+		}
+		return
+	}
+
+	_, _ = s.placeImage(&widget2.CellImage{Image: img}, 0, 0)
+	s.lineDown()
+}
+
+// sixelBackground returns the colour of the pixels that a sixel image does not draw:
+// the current text background, or the default background if there is none.
+func (s *Screen) sixelBackground() color.Color {
+	if bg := widget2.ResolveColor(s.currentBG); bg != nil {
+		return bg
+	}
+	if s.palette.Background != nil {
+		return s.palette.Background
+	}
+	return theme.Color(theme.ColorNameBackground)
+}
+
+// decodeSixel draws sixel data into an image. Pixels that are not drawn are filled with background,
+// or left transparent if it is nil.
+func decodeSixel(data string, background color.Color) image.Image {
+	d := &sixelDecoder{}
+	for i, c := range sixelColors {
+		d.colors[i] = c
+	}
+	d.current = d.colors[0]
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		i++
+		switch {
+		case c >= '?' && c <= '~':
+			d.draw(c-'?', 1)
+		case c == '!':
+			var params []int
+			params, i = sixelParams(data, i)
+			if i < len(data) && len(params) > 0 {
+				d.draw(data[i]-'?', params[0])
+				i++
+			}
+		case c == '#':
+			var params []int
+			params, i = sixelParams(data, i)
+			d.setColor(params)
+		case c == '"':
+			var params []int
+			params, i = sixelParams(data, i)
+			if len(params) >= 4 {
+				d.grow(params[2], params[3])
+				d.width, d.height = d.clip(params[2], params[3])
+			}
+		case c == '$':
+			d.x = 0
+		case c == '-':
+			d.x = 0
+			d.y += 6
+		}
+	}
+
+	if d.img == nil || d.width == 0 || d.height == 0 {
+		return nil
+	}
+	if background != nil {
+		d.fill(color.NRGBAModel.Convert(background).(color.NRGBA))
+	}
+	return d.img.SubImage(image.Rect(0, 0, d.width, d.height))
+}
+
+// draw paints a sixel of the current colour, repeated count times.
+func (d *sixelDecoder) draw(bits byte, count int) {
+	if bits > 63 || count <= 0 {
+		return
+	}
+	if bits == 0 {
+		d.x += count
+		return
+	}
+
+	d.grow(d.x+count, d.y+6)
+	for bit := 0; bit < 6; bit++ {
+		if bits&(1<<bit) == 0 {
+			continue
+		}
+
+		if d.y+bit >= d.height {
+			d.height = d.y + bit + 1
+		}
+		end, _ := d.clip(d.x+count, 0)
+		for x := d.x; x < end; x++ {
+			d.img.SetNRGBA(x, d.y+bit, d.current)
+		}
+	}
+	d.x += count
+	if d.x > d.width {
+		d.width = d.x
+	}
+}
+
+// fill sets the pixels of the image that were not drawn to the colour given.
+func (d *sixelDecoder) fill(c color.NRGBA) {
+	b := d.img.Bounds().Intersect(image.Rect(0, 0, d.width, d.height))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if d.img.NRGBAAt(x, y).A == 0 {
+				d.img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// clip limits a width and height to the size of the image that has been allocated.
+func (d *sixelDecoder) clip(w, h int) (int, int) {
+	if d.img == nil {
+		return 0, 0
+	}
+	if w > d.img.Rect.Dx() {
+		w = d.img.Rect.Dx()
+	}
+	if h > d.img.Rect.Dy() {
+		h = d.img.Rect.Dy()
+	}
+	return w, h
+}
+
+// grow makes sure that the image is at least the given size, doubling it when it needs to be bigger.
+// Images are not made larger than maxSixelSize, to avoid running out of memory.
+func (d *sixelDecoder) grow(w, h int) {
+	if w > maxSixelSize {
+		w = maxSixelSize
+	}
+	if h > maxSixelSize {
+		h = maxSixelSize
+	}
+	if d.img != nil && w <= d.img.Rect.Dx() && h <= d.img.Rect.Dy() {
+		return
+	}
+
+	oldW, oldH := 0, 0
+	if d.img != nil {
+		oldW, oldH = d.img.Rect.Dx(), d.img.Rect.Dy()
+	}
+	newW, newH := oldW, oldH
+	for newW < w {
+		newW = newW*2 + 64
+	}
+	for newH < h {
+		newH = newH*2 + 64
+	}
+	if newW > maxSixelSize {
+		newW = maxSixelSize
+	}
+	if newH > maxSixelSize {
+		newH = maxSixelSize
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, newW, newH))
+	if d.img != nil {
+		for y := 0; y < oldH; y++ {
+			copy(img.Pix[y*img.Stride:y*img.Stride+oldW*4], d.img.Pix[y*d.img.Stride:y*d.img.Stride+oldW*4])
+		}
+	}
+	d.img = img
+}
+
+// setColor selects a colour register, or defines it first if the colour is given in HLS (1) or RGB (2) form.
+func (d *sixelDecoder) setColor(params []int) {
+	if len(params) == 0 || params[0] < 0 || params[0] > 255 {
+		return
+	}
+
+	reg := params[0]
+	if len(params) >= 5 {
+		switch params[1] {
+		case 1:
+			d.colors[reg] = hlsColor(params[2], params[3], params[4])
+		case 2:
+			d.colors[reg] = color.NRGBA{R: sixelPercent(params[2]), G: sixelPercent(params[3]),
+				B: sixelPercent(params[4]), A: 255}
+		}
+	}
+	d.current = d.colors[reg]
+}
+
+// sixelParams reads the numbers separated by ';' that start at index i of the data.
+// It returns them along with the index after the last one.
+func sixelParams(data string, i int) ([]int, int) {
+	end := i
+	for end < len(data) && (data[end] == ';' || (data[end] >= '0' && data[end] <= '9')) {
+		end++
+	}
+
+	var params []int
+	for _, p := range strings.Split(data[i:end], ";") {
+		n := 0
+		for _, c := range p {
+			if n < 1e6 {
+				n = n*10 + int(c-'0')
+			}
+		}
+		params = append(params, n)
+	}
+	return params, end
+}
+
+func sixelPercent(p int) uint8 {
+	if p > 100 {
+		p = 100
+	}
+	return uint8((p*255 + 50) / 100)
+}
+
+// hlsColor converts a DEC HLS colour, where a hue of 0 is blue and lightness and saturation are percentages.
+func hlsColor(hue, lightness, saturation int) color.NRGBA {
+	h := float64((hue+240)%360) / 360 // DEC hues start at blue, rather than red
+	l, s := math.Min(float64(lightness), 100)/100, math.Min(float64(saturation), 100)/100
+	if s == 0 {
+		v := uint8(math.Round(l * 255))
+		return color.NRGBA{R: v, G: v, B: v, A: 255}
+	}
+
+	q := l + s - l*s
+	if l < 0.5 {
+		q = l * (1 + s)
+	}
+	p := 2*l - q
+	channel := func(t float64) uint8 {
+		if t < 0 {
+			t++
+		} else if t > 1 {
+			t--
+		}
+		var v float64
+		switch {
+		case t < 1.0/6:
+			v = p + (q-p)*6*t
+		case t < 0.5:
+			v = q
+		case t < 2.0/3:
+			v = p + (q-p)*(2.0/3-t)*6
+		default:
+			v = p
+		}
+		return uint8(math.Round(v * 255))
+	}
+	return color.NRGBA{R: channel(h + 1.0/3), G: channel(h), B: channel(h - 1.0/3), A: 255}
+}
@@ -0,0 +1,98 @@
+package terminal
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	widget2 "github.com/fyne-io/terminal/internal/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeSixel(t *testing.T) {
+	img := decodeSixel("\"1;1;4;7#1;2;100;0;0#1~~$#2!2?@-#1@", nil)
+	assert.NotNil(t, img)
+	assert.Equal(t, image.Rect(0, 0, 4, 7), img.Bounds())
+
+	red := color.NRGBA{R: 255, A: 255}
+	assert.Equal(t, red, img.At(0, 0))
+	assert.Equal(t, red, img.At(1, 5))
+	assert.Equal(t, sixelColors[2], img.At(2, 0))
+	assert.Equal(t, color.NRGBA{}, img.At(2, 1))
+	assert.Equal(t, color.NRGBA{}, img.At(3, 0))
+	assert.Equal(t, red, img.At(0, 6)) // the first pixel of the next band
+	assert.Equal(t, color.NRGBA{}, img.At(1, 6))
+
+	assert.Nil(t, decodeSixel("#1", nil))
+
+	img = decodeSixel("#1;2;100;0;0#1@?@", color.White)
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(0, 0))
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, img.At(1, 0))
+
+	img = decodeSixel("\"1;1;9999999;5#0~", color.White)
+	assert.Equal(t, image.Rect(0, 0, maxSixelSize, 6), img.Bounds())
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, img.At(maxSixelSize-1, 0))
+}
+
+func TestHLSColor(t *testing.T) {
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, hlsColor(120, 50, 100))
+	assert.Equal(t, color.NRGBA{G: 255, A: 255}, hlsColor(240, 50, 100))
+	assert.Equal(t, color.NRGBA{B: 255, A: 255}, hlsColor(0, 50, 100))
+	assert.Equal(t, color.NRGBA{R: 128, G: 128, B: 128, A: 255}, hlsColor(0, 50, 0))
+}
+
+func TestScreen_Sixel(t *testing.T) {
+	s := NewScreen(10, 4, nil)
+	s.SetCellSize(2, 6)
+	_, _ = s.Write([]byte("ab\x1bPq#1;2;100;0;0#1~~~-~~~\x1b\\c"))
+
+	style, ok := s.Cell(0, 2).Style.(*widget2.TermTextGridStyle)
+	assert.True(t, ok)
+	assert.NotNil(t, style.Image)
+	assert.Equal(t, image.Rect(0, 0, 3, 12), style.Image.Image.Bounds())
+	assert.Equal(t, 2, style.Image.CellWidth)
+	style = s.Cell(1, 3).Style.(*widget2.TermTextGridStyle)
+	assert.Equal(t, 1, style.ImageCol)
+	assert.Equal(t, 1, style.ImageRow)
+	assert.Nil(t, s.Cell(1, 4).Style)
+
+	row, col := s.CursorPosition()
+	assert.Equal(t, 2, row)
+	assert.Equal(t, 3, col)
+	assert.Equal(t, 'c', s.Cell(2, 2).Rune)
+}
+
+func TestScreen_SixelBackslash(t *testing.T) {
+	s := NewScreen(10, 4, nil)
+	s.SetCellSize(2, 6)
+	_, _ = s.Write([]byte("\x1bPq#0;2;100;0;0#0~\\~~\x1b\\c"))
+
+	style, ok := s.Cell(0, 0).Style.(*widget2.TermTextGridStyle)
+	assert.True(t, ok)
+	assert.Equal(t, image.Rect(0, 0, 4, 6), style.Image.Image.Bounds())
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, style.Image.Image.At(1, 0))
+	assert.NotEqual(t, '~', s.Cell(0, 2).Rune)
+	assert.Equal(t, 'c', s.Cell(1, 0).Rune)
+}
+
+func TestScreen_SixelBackground(t *testing.T) {
+	s := NewScreen(10, 4, nil)
+	s.SetCellSize(2, 6)
+	_, _ = s.Write([]byte("\x1b[44m\x1bPq#1;2;100;0;0#1@?@\x1b\\\x1bP0;1q#1@?@\x1b\\"))
+
+	img := s.Cell(0, 0).Style.(*widget2.TermTextGridStyle).Image.Image
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(0, 0))
+	assert.Equal(t, color.NRGBAModel.Convert(basicColors[4]), img.At(1, 0))
+	img = s.Cell(1, 0).Style.(*widget2.TermTextGridStyle).Image.Image
+	assert.Equal(t, color.NRGBA{}, img.At(1, 0))
+}
+
+func TestScreen_SixelAtRightMargin(t *testing.T) {
+	s := NewScreen(4, 3, nil)
+	s.SetCellSize(2, 6)
+	_, _ = s.Write([]byte("abcd\x1bPq\"1;1;1;12#0~-~\x1b\\e"))
+
+	assert.Equal(t, "abcd", s.Text()[:4])
+	row, _ := s.CursorPosition()
+	assert.Equal(t, 2, row)
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
-	"sync"
 	"time"
 	"unicode"
 
@@ -35,69 +34,66 @@ type Config struct {
 	PWD           string
 }
 
-type charSet int
-
-const (
-	charSetANSII charSet = iota
-	charSetDECSpecialGraphics
-	charSetAlternate
-)
-
 // Terminal is a terminal widget that loads a shell and handles input/output.
+// The output is parsed by a Screen, which the Terminal displays.
 type Terminal struct {
 	widget.BaseWidget
 	fyne.ShortcutHandler
-	content      *widget2.TermGrid
-	config       Config
-	listenerLock sync.Mutex
-	listeners    []chan Config
+
+	// OnHyperlink is called when the user holds Ctrl (or Cmd on macOS) and clicks on a hyperlink.
+	// Return true if the link was handled, otherwise http, https and mailto links are opened with fyne.App.OpenURL
+	// and links with other schemes, such as file:, are ignored.
+	OnHyperlink func(id, uri string) bool
+
+	// ClipboardPolicy controls how applications may use the clipboard through OSC 52.
+	// The default allows them to write to it but not to read it.
+	ClipboardPolicy ClipboardPolicy
+	// OnClipboardRequest is called for each clipboard access when the policy is ClipboardAsk.
+	// Call respond with true to allow the read or write, this can happen later, such as when the user
+	// answers a dialog, but must be on the main goroutine.
+	OnClipboardRequest func(read bool, respond func(allow bool))
+	// OnNotification is called when an application asks for a notification to be shown, using OSC 9 or OSC 777.
+	// The title is empty if the application did not set one.
+	OnNotification func(title, body string)
+	// OnCommandFinished is called when the shell reports that a command has finished, see Screen.Commands.
+	OnCommandFinished func(Command)
+
+	screen       *Screen
+	view         *widget2.TermGrid // displays the screen, or the history when scrollOffset > 0
+	scrollOffset int               // number of history lines the view is scrolled back by
 	startDir     string
 
 	pty io.Closer
 	in  io.WriteCloser
 	out io.Reader
 
-	bell, bold, debug, focused bool
-	currentFG, currentBG       color.Color
-	cursorRow, cursorCol       int
-	savedRow, savedCol         int
-	scrollTop, scrollBottom    int
-
-	cursor                   *canvas.Rectangle
-	cursorHidden, bufferMode bool // buffer mode is an xterm extension that impacts control keys
-	cursorMoved              func()
-
-	// Alternate screen buffer (used by curses/fullscreen apps via ?1049h/?47h)
-	altSavedGrid    []widget.TextGridRow // saved main screen rows
-	altSavedRow     int                  // saved cursor row
-	altSavedCol     int                  // saved cursor col
-	altBufferActive bool                 // true when alternate buffer is in use
-
-	onMouseDown, onMouseUp func(int, fyne.KeyModifier, fyne.Position)
-	g0Charset              charSet
-	g1Charset              charSet
-	useG1CharSet           bool
+	bell, focused bool
+	cursor        *canvas.Rectangle
+	search        *searchState
 
 	selStart, selEnd *position
 	selectClipSource *selectClipboard
 	blockMode        bool
 	selecting        bool
 	mouseCursor      desktop.Cursor
+	mouseButton      int      // the button held while reporting mouse events to the application, 0 if none
+	mouseCell        position // the cell of the last mouse event reported, so motion is only sent on a change
+	hoveredLink      *widget2.Hyperlink
 
 	keyboardState struct {
-		shiftPressed bool
-		ctrlPressed  bool
-		altPressed   bool
-	}
-	newLineMode            bool // new line mode or line feed mode
-	bracketedPasteMode     bool
-	disableAutoWrap        bool // disable auto wrap mode (DECAWM off)
-	lastChar               rune // last graphic character output (for CSI b REP)
-	state                  *parseState
+		shiftPressed    bool
+		ctrlPressed     bool
+		altLeftPressed  bool
+		altRightPressed bool
+		superPressed    bool
+	}
+	optionAsAltLeft        bool // on macOS, the left Option key acts as Alt rather than typing characters
+	optionAsAltRight       bool
+	keySent                bool         // a key was sent in place of the character typed with it, so that character is dropped
+	keyHeld                fyne.KeyName // the last key pressed, until it is released
+	keyRepeating           bool         // true once the held key has been typed, so typing it again is a repeat
+	shortcutHandled        bool         // set when a shortcut added to the terminal runs, so it is not sent as a key
 	lastRefresh            time.Time
-	blinking               bool
-	printData              []byte
-	printer                Printer
 	cmd                    *exec.Cmd
 	readWriterConfigurator ReadWriterConfigurator
 }
@@ -120,6 +116,9 @@ func (t *Terminal) Cursor() desktop.Cursor {
 	if t == nil || t.mouseCursor == nil {
 		return desktop.DefaultCursor
 	}
+	if t.hoveredLink != nil {
+		return desktop.PointerCursor
+	}
 	return t.mouseCursor
 }
 
@@ -130,10 +129,7 @@ func (t *Terminal) AcceptsTab() bool {
 
 // AddListener registers a new outgoing channel that will have our Config sent each time it changes.
 func (t *Terminal) AddListener(listener chan Config) {
-	t.listenerLock.Lock()
-	defer t.listenerLock.Unlock()
-
-	t.listeners = append(t.listeners, listener)
+	t.screen.AddListener(listener)
 }
 
 // MinSize provides a size large enough that a terminal could technically funcion.
@@ -148,6 +144,12 @@ func (t *Terminal) MouseDown(ev *desktop.MouseEvent) {
 		c.Focus(t)
 	}
 
+	if ev.Button == desktop.MouseButtonPrimary && ev.Modifier&(fyne.KeyModifierControl|fyne.KeyModifierShortcutDefault) != 0 {
+		if link := t.linkAt(ev.Position); link != nil {
+			t.openLink(link)
+			return
+		}
+	}
 	if t.hasSelectedText() {
 		t.clearSelectedText()
 	}
@@ -155,28 +157,23 @@ func (t *Terminal) MouseDown(ev *desktop.MouseEvent) {
 		t.pasteText(t.selectClipboard())
 	}
 
-	if t.onMouseDown == nil {
+	if t.screen.mouseMode == mouseModeOff {
 		return
 	}
 
-	if ev.Button == desktop.MouseButtonPrimary {
-		t.onMouseDown(1, ev.Modifier, ev.Position)
-	} else if ev.Button == desktop.MouseButtonSecondary {
-		t.onMouseDown(2, ev.Modifier, ev.Position)
+	if btn := mouseButton(ev.Button); btn != 0 {
+		t.mouseDown(btn, ev.Modifier, ev.Position)
 	}
 }
 
 // MouseUp handles the up action for desktop mouse events.
 func (t *Terminal) MouseUp(ev *desktop.MouseEvent) {
-
-	if t.onMouseDown == nil {
+	if t.screen.mouseMode == mouseModeOff {
 		return
 	}
 
-	if ev.Button == desktop.MouseButtonPrimary {
-		t.onMouseUp(1, ev.Modifier, ev.Position)
-	} else if ev.Button == desktop.MouseButtonSecondary {
-		t.onMouseUp(2, ev.Modifier, ev.Position)
+	if btn := mouseButton(ev.Button); btn != 0 {
+		t.mouseUp(btn, ev.Modifier, ev.Position)
 	}
 }
 
@@ -190,11 +187,11 @@ func (t *Terminal) DoubleTapped(pe *fyne.PointEvent) {
 		t.clearSelectedText()
 	}
 
-	if row < 1 || row > len(t.content.Rows) {
+	if row < 1 || row > len(t.view.Rows) {
 		return
 	}
 
-	rowContent := t.content.Rows[row-1].Cells
+	rowContent := t.view.Rows[row-1].Cells
 
 	if col < 0 || col >= len(rowContent) {
 		return // No valid character under the cursor, do nothing
@@ -228,20 +225,7 @@ func (t *Terminal) DoubleTapped(pe *fyne.PointEvent) {
 
 // RemoveListener de-registers a Config channel and closes it
 func (t *Terminal) RemoveListener(listener chan Config) {
-	t.listenerLock.Lock()
-	defer t.listenerLock.Unlock()
-
-	for i, l := range t.listeners {
-		if l == listener {
-			if i < len(t.listeners)-1 {
-				t.listeners = append(t.listeners[:i], t.listeners[i+1:]...)
-			} else {
-				t.listeners = t.listeners[:i]
-			}
-			close(l)
-			return
-		}
-	}
+	t.screen.RemoveListener(listener)
 }
 
 // Resize is called when this terminal widget has been resized.
@@ -250,28 +234,38 @@ func (t *Terminal) Resize(s fyne.Size) {
 	cellSize := t.guessCellSize()
 	cols := uint(math.Floor(float64(s.Width) / float64(cellSize.Width)))
 	rows := uint(math.Floor(float64(s.Height) / float64(cellSize.Height)))
-	if (t.config.Columns == cols) && (t.config.Rows == rows) {
+	if (t.screen.config.Columns == cols) && (t.screen.config.Rows == rows) {
 		return
 	}
 
 	t.BaseWidget.Resize(s)
-	if t.content != nil {
-		t.content.Resize(fyne.NewSize(float32(cols)*cellSize.Width, float32(rows)*cellSize.Height))
+	if t.hasSelectedText() {
+		t.clearSelectedText()
 	}
-
-	oldRows := int(t.config.Rows)
-	t.config.Columns, t.config.Rows = cols, rows
-	if t.scrollBottom == 0 || t.scrollBottom == oldRows-1 {
-		t.scrollBottom = int(t.config.Rows) - 1
+	t.clearSearch()
+	t.screen.SetSize(int(cols), int(rows))
+	scale := float32(1)
+	if c := fyne.CurrentApp().Driver().CanvasForObject(t); c != nil {
+		scale = c.Scale()
+	}
+	t.screen.SetCellSize(int(cellSize.Width*scale), int(cellSize.Height*scale))
+	if t.view != nil {
+		t.view.Resize(fyne.NewSize(float32(cols)*cellSize.Width, float32(rows)*cellSize.Height))
+		t.Refresh()
 	}
-	t.onConfigure()
 
 	t.updatePTYSize()
 }
 
+// Screen returns the model of the terminal output that this widget displays.
+// It can be used to read the cells, cursor and text that applications have written.
+func (t *Terminal) Screen() *Screen {
+	return t.screen
+}
+
 // SetDebug turns on output about terminal codes and other errors if the parameter is `true`.
 func (t *Terminal) SetDebug(debug bool) {
-	t.debug = debug
+	t.screen.SetDebug(debug)
 }
 
 // SetStartDir can be called before one of the Run calls to specify the initial directory.
@@ -288,7 +282,7 @@ func (t *Terminal) Tapped(ev *fyne.PointEvent) {
 
 // Text returns the contents of the buffer as a single string joined with `\n` (no style information).
 func (t *Terminal) Text() string {
-	return t.content.Text()
+	return t.screen.Text()
 }
 
 // ExitCode returns the exit code from the terminal's shell.
@@ -303,9 +297,7 @@ func (t *Terminal) ExitCode() int {
 
 // TouchCancel handles the tap action for mobile apps that lose focus during tap.
 func (t *Terminal) TouchCancel(ev *mobile.TouchEvent) {
-	if t.onMouseUp != nil {
-		t.onMouseUp(1, 0, ev.Position)
-	}
+	t.mouseUp(1, 0, ev.Position)
 }
 
 // TouchDown handles the down action for mobile touch events.
@@ -314,32 +306,16 @@ func (t *Terminal) TouchDown(ev *mobile.TouchEvent) {
 		c.Focus(t)
 	}
 
-	if t.onMouseDown != nil {
-		t.onMouseDown(1, 0, ev.Position)
-	}
+	t.mouseDown(1, 0, ev.Position)
 }
 
 // TouchUp handles the up action for mobile touch events.
 func (t *Terminal) TouchUp(ev *mobile.TouchEvent) {
-	if t.onMouseUp != nil {
-		t.onMouseUp(1, 0, ev.Position)
-	}
-}
-
-func (t *Terminal) onConfigure() {
-	t.listenerLock.Lock()
-	for _, l := range t.listeners {
-		select {
-		case l <- t.config:
-		default:
-			// channel blocked, might be closed
-		}
-	}
-	t.listenerLock.Unlock()
+	t.mouseUp(1, 0, ev.Position)
 }
 
 func (t *Terminal) open() error {
-	for t.config.Columns <= 2 { // wait until it has a valid area
+	for t.screen.config.Columns <= 2 { // wait until it has a valid area
 		time.Sleep(time.Millisecond * 10)
 	}
 	in, out, pty, err := t.startPTY()
@@ -387,7 +363,6 @@ func (t *Terminal) guessCellSize() fyne.Size {
 
 func (t *Terminal) run() {
 	buf := make([]byte, bufLen)
-	var leftOver []byte
 	for {
 		num, err := t.out.Read(buf)
 		if err != nil {
@@ -405,35 +380,31 @@ func (t *Terminal) run() {
 			fyne.LogError("pty read error", err)
 		}
 
-		lenLeftOver := len(leftOver)
-		fullBuf := buf
-		if lenLeftOver > 0 {
-			fullBuf = append(leftOver, buf[:num]...)
-			num += lenLeftOver
-		}
-
-		if t.content == nil {
-			return
-		}
-
-		leftOver = t.handleOutput(fullBuf[:num])
-		if len(leftOver) == 0 || time.Since(t.lastRefresh) > maxRefreshInterval {
-			t.lastRefresh = time.Now()
-			fyne.DoAndWait(t.Refresh)
-		}
+		// apply the whole buffer in one hop to the UI thread, then refresh once
+		fyne.DoAndWait(func() {
+			if t.hasSelectedText() {
+				t.clearSelectedText()
+			}
+			_, _ = t.screen.Write(buf[:num])
+			if len(t.screen.leftOver) == 0 || time.Since(t.lastRefresh) > maxRefreshInterval {
+				t.lastRefresh = time.Now()
+				t.refreshDamage()
+			}
+		})
 	}
 }
 
 // RunLocalShell starts the terminal by loading a shell and starting to process the input/output.
 func (t *Terminal) RunLocalShell() error {
 	if t.startDir != "" {
-		t.config.PWD = t.startDir
+		t.screen.config.PWD = t.startDir
 	} else {
-		t.config.PWD, _ = os.Getwd()
+		t.screen.config.PWD, _ = os.Getwd()
 	}
-	for t.config.Columns == 0 { // don't load the TTY until our output is configured
+	for t.screen.config.Columns == 0 { // don't load the TTY until our output is configured
 		time.Sleep(time.Millisecond * 50)
 	}
+	t.screen.imageFiles = true // the shell runs on this computer, so it can send images as files
 	err := t.open()
 	if err != nil {
 		return err
@@ -447,11 +418,11 @@ func (t *Terminal) RunLocalShell() error {
 // RunWithConnection starts the terminal by connecting to an external resource like an SSH connection.
 func (t *Terminal) RunWithConnection(in io.WriteCloser, out io.Reader) error {
 	if t.startDir != "" {
-		t.config.PWD = t.startDir
+		t.screen.config.PWD = t.startDir
 	} else {
-		t.config.PWD, _ = os.Getwd()
+		t.screen.config.PWD, _ = os.Getwd()
 	}
-	for t.config.Columns == 0 { // don't load the TTY until our output is configured
+	for t.screen.config.Columns == 0 { // don't load the TTY until our output is configured
 		time.Sleep(time.Millisecond * 50)
 	}
 	t.in, t.out = in, out
@@ -465,6 +436,7 @@ func (t *Terminal) RunWithConnection(in io.WriteCloser, out io.Reader) error {
 }
 
 // Write is used to send commands into an open terminal connection.
+// To display output use the Write method of the Screen.
 // Errors will be returned if the connection is not established, has closed, or there was a problem in transmission.
 func (t *Terminal) Write(b []byte) (int, error) {
 	if t.in == nil {
@@ -474,13 +446,22 @@ func (t *Terminal) Write(b []byte) (int, error) {
 	return t.in.Write(b)
 }
 
+// AddShortcut registers a handler for a shortcut typed in the terminal.
+// The shortcut is handled by the terminal, it will not be sent to the application.
+func (t *Terminal) AddShortcut(shortcut fyne.Shortcut, handler func(shortcut fyne.Shortcut)) {
+	t.ShortcutHandler.AddShortcut(shortcut, func(s fyne.Shortcut) {
+		t.shortcutHandled = true
+		handler(s)
+	})
+}
+
 func (t *Terminal) setupShortcuts() {
 	var paste fyne.Shortcut
 	paste = &desktop.CustomShortcut{KeyName: fyne.KeyV, Modifier: fyne.KeyModifierShift | fyne.KeyModifierShortcutDefault}
 	if runtime.GOOS == "darwin" {
 		paste = &fyne.ShortcutPaste{} // we look up clipboard later
 	}
-	t.ShortcutHandler.AddShortcut(paste,
+	t.AddShortcut(paste,
 		func(sh fyne.Shortcut) {
 			clip := fyne.CurrentApp().Clipboard()
 			if ps, ok := sh.(*fyne.ShortcutPaste); ok && ps.Secondary {
@@ -494,12 +475,18 @@ func (t *Terminal) setupShortcuts() {
 		shortcutCopy = &fyne.ShortcutCopy{} // we look up clipboard later
 	}
 
-	t.ShortcutHandler.AddShortcut(shortcutCopy,
+	t.AddShortcut(shortcutCopy,
 		func(_ fyne.Shortcut) {
 			t.copySelectedText(fyne.CurrentApp().Clipboard())
 		})
 }
 
+func (t *Terminal) showNotification(title, body string) {
+	if t.OnNotification != nil {
+		t.OnNotification(title, body)
+	}
+}
+
 func (t *Terminal) startingDir() string {
 	if t.startDir == "" {
 		home, err := os.UserHomeDir()
@@ -517,6 +504,16 @@ func New() *Terminal {
 		mouseCursor: desktop.DefaultCursor,
 		in:          discardWriter{},
 	}
+	t.screen = NewScreen(0, 0, t)
+	t.screen.apcReceived = t.handleAPC
+	t.screen.bellRung = t.ringBell
+	t.screen.clipboardRead = t.readClipboard
+	t.screen.clipboardWritten = t.writeClipboard
+	t.screen.commandFinished = t.finishedCommand
+	t.screen.dirChanged = t.setDirectory
+	t.screen.historyPushed = t.historyPushed
+	t.screen.historyReset = t.historyReset
+	t.screen.notified = t.showNotification
 	t.ExtendBaseWidget(t)
 
 	return t
@@ -545,9 +542,14 @@ func (t *Terminal) sanitizePosition(p fyne.Position) *fyne.Position {
 
 // Dragged is called by fyne when the left mouse is down and moved whilst over the widget.
 func (t *Terminal) Dragged(d *fyne.DragEvent) {
+	if t.screen.mouseMode == mouseModeButton || t.screen.mouseMode == mouseModeAny {
+		t.mouseMoved(0, d.Position)
+		return
+	}
+
 	pos := t.sanitizePosition(d.Position)
 	if !t.selecting {
-		if t.keyboardState.altPressed {
+		if t.keyboardState.altLeftPressed || t.keyboardState.altRightPressed {
 			t.blockMode = true
 		}
 		p := t.getTermPosition(*pos)
@@ -556,7 +558,7 @@ func (t *Terminal) Dragged(d *fyne.DragEvent) {
 	}
 	// clear any previous selection
 	sr, sc, er, ec := t.getSelectedRange()
-	widget2.ClearHighlightRange(t.content, t.blockMode, sr, sc, er, ec)
+	widget2.ClearHighlightRange(t.view, t.blockMode, sr, sc, er, ec)
 
 	// make sure that x,y,x1,y1 are always positive
 	t.selecting = true
@@ -17,7 +17,8 @@ func TestNewTerminal(t *testing.T) {
 	assert.NotNil(t, term)
 
 	term.Refresh() // ensure visuals set up
-	assert.NotNil(t, term.content)
+	assert.NotNil(t, term.screen.content)
+	assert.Same(t, term.screen, term.Screen())
 }
 
 func TestExitCode(t *testing.T) {
@@ -50,24 +51,24 @@ func TestTerminal_Resize(t *testing.T) {
 	term := New()
 	term.Resize(fyne.NewSize(45, 45))
 
-	assert.Equal(t, uint(5), term.config.Columns)
-	assert.Equal(t, uint(2), term.config.Rows)
+	assert.Equal(t, uint(5), term.screen.config.Columns)
+	assert.Equal(t, uint(2), term.screen.config.Rows)
 }
 
 func TestTerminal_AddListener(t *testing.T) {
 	term := New()
 	listen := make(chan Config, 1)
 	term.AddListener(listen)
-	assert.Equal(t, 1, len(term.listeners))
+	assert.Equal(t, 1, len(term.screen.listeners))
 
-	go term.onConfigure()
+	go term.screen.onConfigure()
 	select {
 	case <-listen: // passed
 	case <-time.After(time.Millisecond * 100):
 		t.Error("Failed waiting for configure callback")
 	}
 	term.RemoveListener(listen)
-	assert.Equal(t, 0, len(term.listeners))
+	assert.Equal(t, 0, len(term.screen.listeners))
 }
 
 func TestTerminal_SanitizePosition(t *testing.T) {
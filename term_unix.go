@@ -24,7 +24,7 @@ func (t *Terminal) updatePTYSize() {
 		scale = c.Scale()
 	}
 	_ = pty.Setsize(t.pty.(*os.File), &pty.Winsize{
-		Rows: uint16(t.config.Rows), Cols: uint16(t.config.Columns),
+		Rows: uint16(t.screen.config.Rows), Cols: uint16(t.screen.config.Columns),
 		X: uint16(t.Size().Width * scale), Y: uint16(t.Size().Height * scale)})
 }
 
@@ -40,7 +40,7 @@ func (t *Terminal) startPTY() (io.WriteCloser, io.Reader, io.Closer, error) {
 	c.Dir = t.startingDir()
 	c.Env = env
 	t.cmd = c
-	t.config.PWD = c.Dir
+	t.screen.config.PWD = c.Dir
 
 	go func() {
 		for {
@@ -50,9 +50,9 @@ func (t *Terminal) startPTY() (io.WriteCloser, io.Reader, io.Closer, error) {
 			}
 			wd, _ := os.Readlink("/proc/" + strconv.Itoa(c.Process.Pid) + "/cwd")
 
-			if wd != t.config.PWD {
-				t.config.PWD = wd
-				fyne.Do(t.onConfigure)
+			if wd != t.screen.config.PWD {
+				t.screen.config.PWD = wd
+				fyne.Do(t.screen.onConfigure)
 			}
 		}
 	}()
@@ -14,7 +14,7 @@ func (t *Terminal) updatePTYSize() {
 	if t.pty == nil { // during load
 		return
 	}
-	_ = t.pty.(*conpty.ConPty).Resize(uint16(t.config.Columns), uint16(t.config.Rows))
+	_ = t.pty.(*conpty.ConPty).Resize(uint16(t.screen.config.Columns), uint16(t.screen.config.Rows))
 }
 
 func (t *Terminal) startPTY() (io.WriteCloser, io.Reader, io.Closer, error) {